@@ -0,0 +1,301 @@
+package reago
+
+import (
+	"log"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// App owns the windows of the application and the state and messages they
+// share.
+type App struct {
+	app   fyne.App
+	state *State
+
+	lock        sync.Mutex
+	windows     []*Window
+	subscribers map[string][]*subscriber
+	running     bool
+
+	// autoRun makes the first Window.Show run the app, like NewWindow always did.
+	autoRun         bool
+	quitOnLastClose bool
+	keepAlive       fyne.Window
+
+	stateFile    string
+	windowStates map[string]*windowState
+}
+
+type subscriber struct {
+	callback func(any)
+}
+
+var (
+	defaultLock sync.Mutex
+	defaultApp  *App
+)
+
+// DefaultApp returns the app used by NewWindow: the first one created with
+// NewApp, or else a fyne app with the default driver, created on first use.
+// Built with the ci tag, the default driver is fyne's headless test driver.
+func DefaultApp() *App {
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+
+	if defaultApp == nil {
+		defaultApp = newApp(newDriverApp(""))
+		defaultApp.autoRun = true
+	}
+	return defaultApp
+}
+
+type AppOptions struct {
+	// Driver creates the fyne app, and so picks its driver, e.g. test.NewApp
+	// for headless runs. It is app.NewWithID by default, or test.NewApp when
+	// built with the ci tag, which leaves the native driver out of the build.
+	Driver func(id string) fyne.App
+	// Metadata overrides the one packaged with fyne (FyneApp.toml), used by
+	// notifications and about screens. Its ID defaults to the app id.
+	Metadata *fyne.AppMetadata
+	Icon     fyne.Resource
+	Theme    fyne.Theme
+}
+
+// NewApp creates an app with a unique id, which fyne needs for preferences,
+// storage and notifications. Windows created with App.NewWindow are shown by
+// Window.Show, which doesn't block: they appear on screen once Run starts the
+// app. The first app created becomes the DefaultApp.
+func NewApp(id string, opts AppOptions) *App {
+	if opts.Metadata != nil {
+		metadata := *opts.Metadata
+		if metadata.ID == "" {
+			metadata.ID = id
+		}
+		if metadata.Icon == nil {
+			metadata.Icon = opts.Icon
+		}
+		setMetadata(metadata)
+	}
+
+	driver := opts.Driver
+	if driver == nil {
+		driver = newDriverApp
+	}
+
+	fyneApp := driver(id)
+	if opts.Icon != nil {
+		fyneApp.SetIcon(opts.Icon)
+	}
+	if opts.Theme != nil {
+		fyneApp.Settings().SetTheme(opts.Theme)
+	}
+
+	a := newApp(fyneApp)
+
+	defaultLock.Lock()
+	if defaultApp == nil {
+		defaultApp = a
+	}
+	defaultLock.Unlock()
+
+	return a
+}
+
+// NewAppFrom wraps an existing fyne app, e.g. the one of fyne's test package.
+func NewAppFrom(app fyne.App) *App {
+	return newApp(app)
+}
+
+func newApp(app fyne.App) *App {
+	return &App{
+		app:             app,
+		state:           NewState(),
+		subscribers:     make(map[string][]*subscriber),
+		quitOnLastClose: true,
+	}
+}
+
+// NewWindow creates a window identified by id. Unlike the package level
+// NewWindow, showing it never blocks: call Run once the first windows are shown.
+func (a *App) NewWindow(id string, title string, width float32, height float32) *Window {
+	a.lock.Lock()
+	a.autoRun = false
+	a.lock.Unlock()
+
+	return a.newWindow(id, title, width, height)
+}
+
+func (a *App) newWindow(id string, title string, width float32, height float32) *Window {
+	window := &Window{
+		id:          id,
+		app:         a,
+		menuRefs:    make(map[string]*fyne.MenuItem),
+		menuActions: make(map[string][]*fyne.MenuItem),
+		shortcuts:   make(map[string][]*shortcutBinding),
+		toasts:      newToastHost("bottom-right", 0, nil),
+		hidden:      true,
+	}
+	window.w = a.app.NewWindow(title)
+	window.w.Resize(fyne.NewSize(width, height))
+	window.w.SetOnClosed(window.closed)
+	canvasWindows.Store(window.w.Canvas(), window)
+
+	a.lock.Lock()
+	a.windows = append(a.windows, window)
+	a.lock.Unlock()
+
+	return window
+}
+
+// Window returns the open window with the given id, or nil.
+func (a *App) Window(id string) *Window {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, window := range a.windows {
+		if window.id == id {
+			return window
+		}
+	}
+	return nil
+}
+
+// Windows returns every open window, in creation order.
+func (a *App) Windows() []*Window {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return append([]*Window{}, a.windows...)
+}
+
+func (a *App) CloseAll() {
+	for _, window := range a.Windows() {
+		window.Close()
+	}
+}
+
+// Run shows the app and blocks until it quits.
+func (a *App) Run() {
+	a.lock.Lock()
+	if a.running {
+		a.lock.Unlock()
+		return
+	}
+	a.running = true
+	a.lock.Unlock()
+
+	a.app.Run()
+}
+
+func (a *App) Quit() {
+	a.saveRemembered()
+	a.app.Quit()
+}
+
+func (a *App) saveRemembered() {
+	for _, window := range a.Windows() {
+		window.captureState()
+	}
+	if err := a.saveWindowStates(); err != nil {
+		log.Println("can't save window state:", err)
+	}
+}
+
+// SetQuitOnLastWindowClosed sets whether the app quits when its last window
+// is closed (the default). When false, the app keeps running, e.g. in the
+// system tray, until Quit is called.
+func (a *App) SetQuitOnLastWindowClosed(value bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.quitOnLastClose = value
+
+	// fyne quits once it has no window left, so a hidden one keeps it alive
+	if !value && a.keepAlive == nil {
+		a.keepAlive = a.app.NewWindow("")
+	} else if value && a.keepAlive != nil {
+		a.keepAlive.Close()
+		a.keepAlive = nil
+	}
+}
+
+// UseState returns a state shared by the whole app. Pass it to
+// NewDOMWithState so DOMs in different windows stay in sync.
+func (a *App) UseState() *State {
+	return a.state
+}
+
+// Subscribe registers a callback for messages sent to topic. The returned
+// function unsubscribes it.
+func (a *App) Subscribe(topic string, callback func(message any)) func() {
+	sub := &subscriber{callback: callback}
+
+	a.lock.Lock()
+	a.subscribers[topic] = append(a.subscribers[topic], sub)
+	a.lock.Unlock()
+
+	return func() {
+		a.lock.Lock()
+		defer a.lock.Unlock()
+
+		subs := a.subscribers[topic]
+		for i, other := range subs {
+			if other == sub {
+				a.subscribers[topic] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Send delivers message to every subscriber of topic.
+func (a *App) Send(topic string, message any) {
+	a.lock.Lock()
+	subs := append([]*subscriber{}, a.subscribers[topic]...)
+	a.lock.Unlock()
+
+	for _, sub := range subs {
+		sub.callback(message)
+	}
+}
+
+func (a *App) removeWindow(window *Window) {
+	a.lock.Lock()
+
+	for i, other := range a.windows {
+		if other == window {
+			a.windows = append(a.windows[:i:i], a.windows[i+1:]...)
+			break
+		}
+	}
+	quit := len(a.windows) == 0 && a.quitOnLastClose && a.running
+
+	a.lock.Unlock()
+
+	if quit {
+		a.app.Quit()
+	}
+}
+
+// modalBlocker covers the parent of a modal window, swallowing its input.
+type modalBlocker struct {
+	widget.BaseWidget
+	modal *Window
+}
+
+func newModalBlocker(modal *Window) *modalBlocker {
+	blocker := &modalBlocker{modal: modal}
+	blocker.ExtendBaseWidget(blocker)
+	return blocker
+}
+
+func (blocker *modalBlocker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(theme.Color(theme.ColorNameShadow)))
+}
+
+func (blocker *modalBlocker) Tapped(*fyne.PointEvent) {
+	blocker.modal.RequestFocus()
+}
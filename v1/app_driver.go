@@ -0,0 +1,21 @@
+//go:build !ci
+
+package reago
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+)
+
+// newDriverApp creates the fyne app of DefaultApp and NewApp, with the native
+// driver of the platform.
+func newDriverApp(id string) fyne.App {
+	if id == "" {
+		return app.New()
+	}
+	return app.NewWithID(id)
+}
+
+func setMetadata(metadata fyne.AppMetadata) {
+	app.SetMetadata(metadata)
+}
@@ -0,0 +1,17 @@
+//go:build ci
+
+package reago
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+// newDriverApp creates the fyne app of DefaultApp and NewApp. Built with the
+// ci tag it is fyne's headless test app, so no native driver is linked in.
+func newDriverApp(string) fyne.App {
+	return test.NewApp()
+}
+
+// setMetadata does nothing, the test app has no packaged metadata.
+func setMetadata(fyne.AppMetadata) {}
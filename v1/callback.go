@@ -0,0 +1,297 @@
+package reago
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	eventType = reflect.TypeOf((*Event)(nil))
+	nodeType  = reflect.TypeOf((*XMLNode)(nil))
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// callExpr is a parsed callback binding, e.g. `remove(item.ID, "soft")`.
+type callExpr struct {
+	name string
+	args []string
+	// hasArgs tells `save()` apart from `save`, which receives no arguments
+	// from the template but can still ask for the event, node or item.
+	hasArgs bool
+}
+
+var callExprs sync.Map // map[string]*callExpr
+
+func parseCallExpr(bind string) (*callExpr, error) {
+	if cached, ok := callExprs.Load(bind); ok {
+		return cached.(*callExpr), nil
+	}
+
+	expr := &callExpr{name: strings.TrimSpace(bind)}
+
+	if open := strings.Index(bind, "("); open != -1 {
+		if !strings.HasSuffix(strings.TrimSpace(bind), ")") {
+			return nil, errors.New("callback binding is missing a closing parenthesis: " + bind)
+		}
+
+		expr.name = strings.TrimSpace(bind[:open])
+		expr.hasArgs = true
+
+		args, err := splitArgs(strings.TrimSuffix(strings.TrimSpace(bind[open+1:]), ")"))
+		if err != nil {
+			return nil, fmt.Errorf("callback binding %s: %w", bind, err)
+		}
+		expr.args = args
+	}
+
+	callExprs.Store(bind, expr)
+	return expr, nil
+}
+
+// splitArgs splits a comma separated argument list, keeping quoted strings
+// together.
+func splitArgs(str string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+
+	for _, r := range str {
+		switch {
+		case quote != 0:
+			current.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			current.WriteRune(r)
+		case r == ',':
+			args = append(args, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated string")
+	}
+	if last := strings.TrimSpace(current.String()); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+
+	return args, nil
+}
+
+// evalArg evaluates a template argument: a quoted string, a number, a bool,
+// `event`, `node`, `item` (or a field path such as `item.User.Name`), or the
+// name of a state value.
+func (dom *DOM) evalArg(arg string, event *Event) (any, error) {
+	if arg == "" {
+		return nil, errors.New("empty argument")
+	}
+
+	if (arg[0] == '"' || arg[0] == '\'') && len(arg) > 1 && arg[len(arg)-1] == arg[0] {
+		return arg[1 : len(arg)-1], nil
+	}
+
+	switch arg {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "nil", "null":
+		return nil, nil
+	case "event":
+		return event, nil
+	case "node":
+		return event.Node, nil
+	}
+
+	if i, err := strconv.Atoi(arg); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(arg, 64); err == nil {
+		return f, nil
+	}
+
+	path := strings.Split(arg, ".")
+	if path[0] == "item" {
+		return fieldPath(event.Item, path[1:])
+	}
+
+	value, ok := dom.state.Value(path[0])
+	if !ok {
+		return nil, errors.New("unknown argument: " + arg)
+	}
+	return fieldPath(value, path[1:])
+}
+
+// fieldPath follows struct fields and map keys from value.
+func fieldPath(value any, path []string) (any, error) {
+	current := reflect.ValueOf(value)
+
+	for _, name := range path {
+		for current.Kind() == reflect.Pointer || current.Kind() == reflect.Interface {
+			if current.IsNil() {
+				return nil, errors.New("nil value at " + name)
+			}
+			current = current.Elem()
+		}
+
+		switch current.Kind() {
+		case reflect.Struct:
+			field := current.FieldByName(name)
+			if !field.IsValid() || !field.CanInterface() {
+				return nil, errors.New("unknown field " + name)
+			}
+			current = field
+		case reflect.Map:
+			field := current.MapIndex(reflect.ValueOf(name))
+			if !field.IsValid() {
+				return nil, errors.New("unknown key " + name)
+			}
+			current = field
+		default:
+			return nil, errors.New("can't access " + name + " on " + current.Kind().String())
+		}
+	}
+
+	if !current.IsValid() {
+		return nil, nil
+	}
+	return current.Interface(), nil
+}
+
+// callHandler calls a handler registered with UseHandler. Template arguments
+// are converted to the parameter types; without arguments, parameters are
+// filled by type with the event, the node and the item.
+func (dom *DOM) callHandler(handler reflect.Value, expr *callExpr, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("callback %s panicked: %v\n%s", expr.name, r, debug.Stack())
+		}
+	}()
+
+	fnType := handler.Type()
+	fixed := fnType.NumIn()
+	if fnType.IsVariadic() {
+		fixed--
+	}
+
+	var in []reflect.Value
+	if expr.hasArgs {
+		if len(expr.args) < fixed || (len(expr.args) > fixed && !fnType.IsVariadic()) {
+			return fmt.Errorf("callback %s expects %d arguments, got %d", expr.name, fixed, len(expr.args))
+		}
+		in = make([]reflect.Value, len(expr.args))
+		for i, arg := range expr.args {
+			paramType := fnType.In(min(i, fnType.NumIn()-1))
+			if i >= fixed {
+				paramType = paramType.Elem()
+			}
+
+			value, err := dom.evalArg(arg, event)
+			if err != nil {
+				return fmt.Errorf("callback %s: %w", expr.name, err)
+			}
+			in[i], err = convertArg(value, paramType)
+			if err != nil {
+				return fmt.Errorf("callback %s argument %d: %w", expr.name, i+1, err)
+			}
+		}
+	} else {
+		// a variadic parameter is left empty
+		in = make([]reflect.Value, fixed)
+		for i := range in {
+			paramType := fnType.In(i)
+			switch {
+			case paramType == eventType:
+				in[i] = reflect.ValueOf(event)
+			case paramType == nodeType:
+				in[i] = reflect.ValueOf(event.Node)
+			case event.Item != nil && reflect.TypeOf(event.Item).AssignableTo(paramType):
+				in[i] = reflect.ValueOf(event.Item)
+			default:
+				in[i] = reflect.Zero(paramType)
+			}
+		}
+	}
+
+	out := handler.Call(in)
+	if len(out) > 0 {
+		last := out[len(out)-1]
+		if last.Type() == errorType && !last.IsNil() {
+			return last.Interface().(error)
+		}
+	}
+
+	return nil
+}
+
+func convertArg(value any, to reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(to), nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(to) {
+		return v, nil
+	}
+
+	// strings from state or struct fields into numbers and bools
+	if str, ok := value.(string); ok {
+		switch to.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(i).Convert(to), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(u).Convert(to), nil
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(f).Convert(to), nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(str)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(b), nil
+		}
+	}
+
+	if to.Kind() == reflect.String {
+		return reflect.ValueOf(fmt.Sprintf("%v", value)).Convert(to), nil
+	}
+
+	if v.Type().ConvertibleTo(to) {
+		return v.Convert(to), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("can't use %T as %s", value, to)
+}
+
+// reportError sends a callback error to the handler set with OnError, or
+// logs it.
+func (dom *DOM) reportError(err error) {
+	if dom.onError != nil {
+		dom.onError(err)
+		return
+	}
+	log.Println("callback error:", err)
+}
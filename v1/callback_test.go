@@ -0,0 +1,156 @@
+package reago_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestCallbackArguments(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseState().String("label", "total")
+	result := dom.UseState().String("result", "")
+	dom.UseHandler("describe", func(name string, count int, ratio float64, ok bool) {
+		result.Set(fmt.Sprintf("%s %d %g %t", name, count, ratio, ok))
+	})
+	dom.Template(`<button bind:click="describe(label, 3, 0.5, true)">Describe</button>`)
+	h.Mount(dom)
+
+	h.Click("button")
+	h.AssertState("result", "total 3 0.5 true")
+}
+
+func TestCallbackQuotedArguments(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	result := dom.UseState().String("result", "")
+	dom.UseHandler("join", func(a string, b string) {
+		result.Set(a + "|" + b)
+	})
+	dom.Template(`<button bind:click="join('a, b', &quot;c&quot;)">Join</button>`)
+	h.Mount(dom)
+
+	h.Click("button")
+	h.AssertState("result", "a, b|c")
+}
+
+func TestCallbackVariadic(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	total := dom.UseState().Int("total", -1)
+	dom.UseHandler("sum", func(values ...int) {
+		sum := 0
+		for _, value := range values {
+			sum += value
+		}
+		total.Set(sum)
+	})
+	dom.Template(`
+		<col>
+			<button bind:click="sum(1, 2, 3)">Some</button>
+			<button bind:click="sum()">None</button>
+		</col>
+	`)
+	h.Mount(dom)
+
+	h.Click("button:contains(Some)")
+	h.AssertState("total", 6)
+	h.Click("button:contains(None)")
+	h.AssertState("total", 0)
+}
+
+func TestCallbackItemArgument(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseState().List("users", []any{user{ID: 1, Name: "Ana"}, user{ID: 2, Name: "Bob"}})
+	removed := dom.UseState().Int("removed", 0)
+	dom.UseHandler("remove", func(id int) {
+		removed.Set(id)
+	})
+	dom.Template(`
+		<list bind:items="users">
+			<button bind:click="remove(item.ID)">Remove {{Name}}</button>
+		</list>
+	`)
+	h.Mount(dom)
+
+	// list rows are rendered outside of the DOM, so they can't be queried
+	var remove fyne.Tappable
+	for _, obj := range test.LaidOutObjects(h.Find("list")) {
+		if text, ok := obj.(*canvas.Text); ok && text.Text == "Remove Bob" {
+			break
+		}
+		if tappable, ok := obj.(fyne.Tappable); ok {
+			remove = tappable
+		}
+	}
+	if remove == nil {
+		t.Fatal("no button in the list")
+	}
+	test.Tap(remove)
+	h.AssertState("removed", 2)
+}
+
+func TestCallbackErrors(t *testing.T) {
+	h := reagotest.New(t)
+
+	var reported []error
+	dom := reago.NewDOM()
+	dom.OnError(func(err error) {
+		reported = append(reported, err)
+	})
+	dom.UseHandler("fail", func() error {
+		return errors.New("failed")
+	})
+	dom.UseHandler("explode", func() {
+		panic("boom")
+	})
+	dom.UseHandler("one", func(value int) {})
+	dom.Template(`
+		<col>
+			<button bind:click="fail">Fail</button>
+			<button bind:click="explode">Explode</button>
+			<button bind:click="one(1, 2)">Extra</button>
+			<button bind:click="missing">Missing</button>
+		</col>
+	`)
+	h.Mount(dom)
+
+	for _, label := range []string{"Fail", "Explode", "Extra", "Missing"} {
+		h.Click("button:contains(" + label + ")")
+	}
+
+	if len(reported) != 4 {
+		t.Fatalf("%d errors reported, expected 4: %v", len(reported), reported)
+	}
+	if reported[0].Error() != "failed" {
+		t.Errorf("returned error reported as %q", reported[0])
+	}
+	if !strings.Contains(reported[1].Error(), "boom") || !strings.Contains(reported[1].Error(), "callback_test.go") {
+		t.Errorf("panic reported without its stack: %q", reported[1])
+	}
+	if !strings.Contains(reported[2].Error(), "expects 1 arguments, got 2") {
+		t.Errorf("extra argument reported as %q", reported[2])
+	}
+	if !strings.Contains(reported[3].Error(), "unknown callback: missing") {
+		t.Errorf("unknown callback reported as %q", reported[3])
+	}
+}
@@ -0,0 +1,49 @@
+package reago
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock schedules the timers of ReaGO, such as the dismissal of toasts.
+// Tests replace it with SetClock to control time.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type Timer interface {
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+var (
+	clockLock sync.RWMutex
+	clock     Clock = realClock{}
+)
+
+// SetClock replaces the clock used for timers; nil restores the real one.
+func SetClock(c Clock) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	if c == nil {
+		c = realClock{}
+	}
+	clock = c
+}
+
+func currentClock() Clock {
+	clockLock.RLock()
+	defer clockLock.RUnlock()
+	return clock
+}
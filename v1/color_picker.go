@@ -0,0 +1,98 @@
+package reago
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// swatchColors are the colors offered by the picker of PickColor.
+var swatchColors = []color.Color{
+	color.NRGBA{0xf4, 0x43, 0x36, 0xff}, // red
+	color.NRGBA{0xff, 0x98, 0x00, 0xff}, // orange
+	color.NRGBA{0xff, 0xeb, 0x3b, 0xff}, // yellow
+	color.NRGBA{0x8b, 0xc3, 0x4a, 0xff}, // green
+	color.NRGBA{0x29, 0x62, 0xff, 0xff}, // blue
+	color.NRGBA{0x9c, 0x27, 0xb0, 0xff}, // purple
+	color.NRGBA{0x79, 0x55, 0x48, 0xff}, // brown
+	color.NRGBA{0x00, 0x00, 0x00, 0xff}, // black
+	color.NRGBA{0x80, 0x80, 0x80, 0xff}, // grey
+	color.NRGBA{0xff, 0xff, 0xff, 0xff}, // white
+}
+
+// colorPicker is the content of the PickColor dialog: swatches, a hex entry
+// and a preview of the picked color.
+type colorPicker struct {
+	color   color.Color
+	preview *canvas.Rectangle
+	hex     *widget.Entry
+}
+
+func newColorPicker(initial color.Color) *colorPicker {
+	picker := &colorPicker{
+		preview: canvas.NewRectangle(initial),
+		hex:     widget.NewEntry(),
+	}
+	picker.preview.SetMinSize(fyne.NewSquareSize(theme.IconInlineSize() * 2))
+	picker.hex.OnChanged = func(text string) {
+		if c, err := Parser.ParseColor(text); err == nil {
+			picker.color = c
+			picker.preview.FillColor = c
+			picker.preview.Refresh()
+		}
+	}
+	picker.set(initial)
+	return picker
+}
+
+// set picks c, updating the preview and the hex entry.
+func (picker *colorPicker) set(c color.Color) {
+	r, g, b, _ := c.RGBA()
+	picker.hex.SetText(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+	picker.color = c
+	picker.preview.FillColor = c
+	picker.preview.Refresh()
+}
+
+func (picker *colorPicker) content(message string) fyne.CanvasObject {
+	swatches := container.NewGridWithColumns(len(swatchColors) / 2)
+	for _, c := range swatchColors {
+		swatches.Add(newColorSwatch(c, picker.set))
+	}
+
+	content := container.NewVBox(swatches, container.NewBorder(nil, nil, picker.preview, nil, picker.hex))
+	if message != "" {
+		content.Objects = append([]fyne.CanvasObject{widget.NewLabel(message)}, content.Objects...)
+	}
+	return content
+}
+
+// colorSwatch is a tappable square of color.
+type colorSwatch struct {
+	widget.BaseWidget
+	color    color.Color
+	onTapped func(color.Color)
+}
+
+func newColorSwatch(c color.Color, onTapped func(color.Color)) *colorSwatch {
+	swatch := &colorSwatch{color: c, onTapped: onTapped}
+	swatch.ExtendBaseWidget(swatch)
+	return swatch
+}
+
+func (swatch *colorSwatch) Tapped(*fyne.PointEvent) {
+	swatch.onTapped(swatch.color)
+}
+
+func (swatch *colorSwatch) CreateRenderer() fyne.WidgetRenderer {
+	rect := canvas.NewRectangle(swatch.color)
+	rect.StrokeColor = theme.Color(theme.ColorNameShadow)
+	rect.StrokeWidth = 1
+	rect.SetMinSize(fyne.NewSquareSize(theme.IconInlineSize() * 1.5))
+	return widget.NewSimpleRenderer(rect)
+}
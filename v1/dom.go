@@ -1,118 +1,361 @@
 package reago
 
 import (
-	"log"
+	"errors"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
-	"github.com/fsnotify/fsnotify"
 )
 
 type DOM struct {
-	root      *fyne.Container
-	refs      map[string]fyne.CanvasObject
-	state     *State
-	callbacks map[string]func(*XMLNode)
+	root         *fyne.Container
+	refs         map[string]fyne.CanvasObject
+	menuRefs     map[string]*fyne.MenuItem
+	menuActions  map[string][]*fyne.MenuItem
+	state        *State
+	handlers     map[string]reflect.Value
+	onError      func(error)
+	menus        map[string]*fyne.Menu
+	shortcuts    []*shortcutBinding
+	window       *Window
+	trees        map[string]*treeSource
+	assets       []fs.FS
+	files        fs.FS
+	devDir       string
+	templates    map[string]string
+	router       *Router
+	deepLinks    []string
+	routeView    *routerView
+	routerViews  []*routerView
+	routeBase    string
+	item         any
+	owners       []fyne.CanvasObject
+	toasts       *toastHost
+	elements     []*Element
+	scope        *elementScope
+	file         string
+	includeView  *includeView
+	includes     []*includeView
+	includeCache *includeCache
+	source       string
+	fragment     bool
+	watcher      *Watcher
+	reloadErrors map[string]error
+	parseMode    ParseMode
+}
+
+type treeSource struct {
+	children func(id string) []string
+	isBranch func(id string) bool
 }
 
 func NewDOM() *DOM {
 	dom := &DOM{
-		root:      container.NewStack(nil),
-		refs:      make(map[string]fyne.CanvasObject),
-		state:     NewState(),
-		callbacks: make(map[string]func(*XMLNode)),
+		root:        container.NewStack(nil),
+		refs:        make(map[string]fyne.CanvasObject),
+		menuRefs:    make(map[string]*fyne.MenuItem),
+		menuActions: make(map[string][]*fyne.MenuItem),
+		state:       NewState(),
+		handlers:    make(map[string]reflect.Value),
+		menus:       make(map[string]*fyne.Menu),
+		trees:       make(map[string]*treeSource),
+		templates:   make(map[string]string),
+		includeCache: &includeCache{
+			files: make(map[string]cachedInclude),
+		},
 	}
 	return dom
 }
 
+// NewDOMWithState creates a DOM bound to an existing state, e.g. the one
+// returned by App.UseState, so several windows share their values.
+func NewDOMWithState(state *State) *DOM {
+	dom := NewDOM()
+	dom.state = state
+	return dom
+}
+
 func (dom *DOM) Clone() *DOM {
 	clone := NewDOM()
 	for name, reactive := range dom.state.binds {
 		clone.state.binds[name] = reactive // might have to clone each one?
 	}
-	for name, callback := range dom.callbacks {
-		clone.callbacks[name] = callback
+	for name, handler := range dom.handlers {
+		clone.handlers[name] = handler
 	}
+	clone.onError = dom.onError
+	for name, menu := range dom.menus {
+		clone.menus[name] = menu
+	}
+	for name, source := range dom.trees {
+		clone.trees[name] = source
+	}
+	for name, content := range dom.templates {
+		clone.templates[name] = content
+	}
+	clone.assets = dom.assets
+	clone.files = dom.files
+	clone.devDir = dom.devDir
+	clone.parseMode = dom.parseMode
+	clone.deepLinks = dom.deepLinks
+	clone.router = dom.router
+	clone.item = dom.item
+	clone.owners = dom.owners
+	clone.file = dom.file
+	clone.includeView = dom.includeView
+	clone.includeCache = dom.includeCache
+	clone.watcher = dom.watcher
+	clone.fragment = true
 	return clone
 }
 
+// fragments returns a function cloning the DOM for the items of a <list> or
+// <tree>, which are rendered after the tag, so that their includes resolve
+// relative to the include the tag is in. The list or tree is passed as owner,
+// as its items can't be reached from it.
+func (dom *DOM) fragments() func(owner fyne.CanvasObject) *DOM {
+	view := dom.includeView
+	return func(owner fyne.CanvasObject) *DOM {
+		clone := dom.Clone()
+		clone.includeView = view
+		clone.owners = append(slices.Clone(dom.owners), owner)
+		return clone
+	}
+}
+
 func (dom *DOM) UseState() *State {
 	return dom.state
 }
 
 func (dom *DOM) UseCallback(name string, callback func(node *XMLNode)) {
-	dom.callbacks[name] = callback
+	dom.UseHandler(name, callback)
 }
 
-func (dom *DOM) FileTemplate(path string, watch bool) {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		log.Fatal(err)
-	}
+// UseItemCallback registers a callback that also receives the data of the
+// <list> or <tree> item it was triggered from (nil outside of them).
+func (dom *DOM) UseItemCallback(name string, callback func(node *XMLNode, item any)) {
+	dom.UseHandler(name, callback)
+}
+
+// UseMenu registers a menu that can be attached to any tag with
+// bind:contextmenu="name".
+func (dom *DOM) UseMenu(name string, menu *fyne.Menu) {
+	dom.menus[name] = menu
+}
 
-	dom.Template(readXMLFile(absPath))
+// UseEventCallback registers a callback that receives the event that
+// triggered it, e.g. the key of a bind:keydown.
+func (dom *DOM) UseEventCallback(name string, callback func(event *Event)) {
+	dom.UseHandler(name, callback)
+}
 
-	if watch {
-		go watchFile(absPath, func(event fsnotify.Event) {
-			log.Println("File " + path + " changed")
-			dom.Template(readXMLFile(absPath))
-		})
+// UseHandler registers any function as a callback. Arguments declared in
+// the template, e.g. bind:click="remove(item.ID)", are converted to its
+// parameter types. Without arguments, parameters of type *Event, *XMLNode
+// and the type of the current <list> or <tree> item are filled in. The
+// template arguments left over after the regular parameters are passed to a
+// variadic fn. A non-nil error returned by fn is sent to the OnError handler.
+func (dom *DOM) UseHandler(name string, fn any) {
+	handler := reflect.ValueOf(fn)
+	if handler.Kind() != reflect.Func {
+		panic("reago: handler " + name + " is not a function")
 	}
+	dom.handlers[name] = handler
 }
 
-func (dom *DOM) Template(content string) {
-	dom.refs = make(map[string]fyne.CanvasObject)
-	dom.root.Objects = []fyne.CanvasObject{Parser.ParseXML(content, dom)}
-	dom.root.Refresh()
+// OnError sets the handler for errors returned by callbacks or caused by
+// invalid callback bindings. By default they are logged.
+func (dom *DOM) OnError(handler func(err error)) {
+	dom.onError = handler
 }
 
-func (dom *DOM) AppendTo(parent *fyne.Container) {
-	parent.Add(dom.root)
+func (dom *DOM) invoke(bind string, node *XMLNode) {
+	dom.dispatch(bind, &Event{Node: node})
 }
 
-func readXMLFile(path string) string {
-	bytes, err := os.ReadFile(path)
+// dispatch calls the callback of a binding such as `save` or
+// `remove(item.ID)`.
+func (dom *DOM) dispatch(bind string, event *Event) {
+	if event.Item == nil {
+		event.Item = dom.item
+	}
+
+	expr, err := parseCallExpr(bind)
 	if err != nil {
-		panic(err)
+		dom.reportError(err)
+		return
+	}
+
+	handler, ok := dom.handlers[expr.name]
+	if !ok {
+		dom.reportError(errors.New("unknown callback: " + expr.name))
+		return
+	}
+	if err := dom.callHandler(handler, expr, event); err != nil {
+		dom.reportError(err)
 	}
-	return string(bytes)
 }
 
-func watchFile(filename string, callback func(event fsnotify.Event)) {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		log.Fatal(err)
+// UseTreeSource registers a lazily loaded tree under name, so it can be used
+// with <tree bind:nodes="name">. The root node has the id "".
+func (dom *DOM) UseTreeSource(name string, children func(id string) []string, isBranch func(id string) bool) {
+	dom.trees[name] = &treeSource{children: children, isBranch: isBranch}
+}
+
+// UseAssets registers a file system (usually an embed.FS) where image
+// sources are looked up before the global Icons registry and the disk.
+func (dom *DOM) UseAssets(fsys fs.FS) {
+	dom.assets = append(dom.assets, fsys)
+}
+
+// UseParseMode sets how the templates of the DOM are parsed, ParseStrict by
+// default.
+func (dom *DOM) UseParseMode(mode ParseMode) {
+	dom.parseMode = mode
+}
+
+// UseTemplate registers a named template, which can be used by
+// <route template="name">.
+func (dom *DOM) UseTemplate(name string, content string) {
+	dom.templates[name] = content
+}
+
+// UseFS sets the file system (usually an embed.FS) FileTemplate and image
+// sources are read from. Without it, they are read from the disk.
+func (dom *DOM) UseFS(fsys fs.FS) {
+	dom.files = fsys
+}
+
+// UseDevDir reads files from dir on disk instead of the FS set by UseFS, e.g.
+// from the directory embedded in release builds, so they can be edited and
+// watched while developing. It is ignored if dir doesn't exist, which is the
+// case when the app is installed elsewhere.
+func (dom *DOM) UseDevDir(dir string) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dom.devDir = ""
+		return
 	}
-	defer watcher.Close()
+	dom.devDir = dir
+}
 
-	// Watch the directory that contains the file.
-	dir := filepath.Dir(filename)
-	err = watcher.Add(dir)
+// readFile reads name from the dev dir, the FS or the disk, in that order.
+func (dom *DOM) readFile(name string) ([]byte, error) {
+	if path, ok := dom.diskPath(name); ok {
+		return os.ReadFile(path)
+	}
+	return fs.ReadFile(dom.files, strings.TrimPrefix(path.Clean(name), "/"))
+}
+
+// diskPath returns where name is on disk, unless it is read from the FS.
+func (dom *DOM) diskPath(name string) (string, bool) {
+	if dom.devDir != "" {
+		return filepath.Join(dom.devDir, filepath.FromSlash(path.Clean("/"+name))), true
+	}
+	if dom.files == nil {
+		return name, true
+	}
+	return "", false
+}
+
+// FileTemplate renders the template in the file path (see UseFS), or returns
+// the error reading or parsing it. Its <include> tags are resolved relative
+// to it. With watch, the files read from the disk are hot reloaded (see
+// Watcher) until the returned watcher is stopped; it is nil otherwise.
+func (dom *DOM) FileTemplate(path string, watch bool) (*Watcher, error) {
+	content, err := dom.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	root, err := Parser.parseRoot(string(content), dom.parseMode)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	for {
-		select {
-		case event, ok := <-watcher.Events:
-			if !ok {
-				return
-			}
-			// Compare absolute paths to ensure a match.
-			eventPath, err := filepath.Abs(event.Name)
+	if watch && (dom.watcher == nil || dom.watcher.stopped()) {
+		if _, onDisk := dom.diskPath(path); onDisk {
+			watcher, err := newWatcher(dom.fileChanged)
 			if err != nil {
-				continue
-			}
-			if eventPath == filename {
-				callback(event)
+				return nil, err
 			}
-		case err, ok := <-watcher.Errors:
-			if !ok {
-				return
-			}
-			log.Println("Watcher error:", err)
+			dom.watcher = watcher
 		}
 	}
+
+	dom.render(string(content), path, func() fyne.CanvasObject {
+		return Parser.ParseNode(root, dom)
+	})
+
+	if !watch || dom.watcher == nil || dom.watcher.stopped() {
+		return nil, nil
+	}
+	return dom.watcher, nil
+}
+
+func (dom *DOM) Template(content string) {
+	dom.template(content, "")
+}
+
+func (dom *DOM) template(content string, file string) {
+	dom.render(content, file, func() fyne.CanvasObject {
+		return Parser.ParseXML(content, dom)
+	})
+}
+
+// render replaces what the DOM shows with the object parse returns for the
+// template content, read from file.
+func (dom *DOM) render(content string, file string, parse func() fyne.CanvasObject) {
+	if dom.window != nil {
+		dom.window.captureState()
+	}
+
+	dom.refs = make(map[string]fyne.CanvasObject)
+	dom.shortcuts = nil
+	dom.toasts = nil
+	dom.elements = nil
+	dom.removeRouterViews()
+	removeIncludes(dom.includes)
+	dom.includes = nil
+
+	dom.file = file
+	dom.source = content
+	dom.watchFile(file)
+	dom.reloadErrors = nil
+	dom.root.Objects = []fyne.CanvasObject{parse()}
+	dom.root.Refresh()
+
+	if dom.window != nil {
+		dom.window.syncShortcuts()
+		dom.window.restoreLayout()
+	}
+}
+
+// removeRouterViews removes the <router> views this DOM mounted from the
+// router, which clones share.
+func (dom *DOM) removeRouterViews() {
+	if dom.router != nil {
+		dom.router.removeViews(dom.routerViews)
+	}
+	dom.routerViews = nil
+}
+
+// fileKey identifies a file however it is named: by its absolute path if it
+// is read from the disk, or else by its clean path in the FS.
+func (dom *DOM) fileKey(name string) string {
+	if diskPath, onDisk := dom.diskPath(name); onDisk {
+		if absPath, err := filepath.Abs(diskPath); err == nil {
+			return absPath
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func (dom *DOM) AppendTo(parent *fyne.Container) {
+	parent.Add(dom.root)
 }
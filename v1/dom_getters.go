@@ -44,18 +44,27 @@ func (dom *DOM) GetText(id string) *canvas.Text {
 }
 
 func (dom *DOM) GetButton(id string) *widget.Button {
+	if obj := cast[*keyButton](dom.refs, id); obj != nil {
+		return &obj.Button
+	}
 	return cast[*widget.Button](dom.refs, id)
 }
 
 func (dom *DOM) GetInput(id string) *widget.Entry {
+	if entry := cast[*inputEntry](dom.refs, id); entry != nil {
+		return &entry.Entry
+	}
 	return cast[*widget.Entry](dom.refs, id)
 }
 
 func (dom *DOM) GetTextarea(id string) *widget.Entry {
-	return cast[*widget.Entry](dom.refs, id)
+	return dom.GetInput(id)
 }
 
 func (dom *DOM) GetCheckbox(id string) *widget.Check {
+	if obj := cast[*keyCheck](dom.refs, id); obj != nil {
+		return &obj.Check
+	}
 	return cast[*widget.Check](dom.refs, id)
 }
 
@@ -76,6 +85,9 @@ func (dom *DOM) GetCard(id string) *widget.Card {
 }
 
 func (dom *DOM) GetA(id string) *widget.Hyperlink {
+	if obj := cast[*keyHyperlink](dom.refs, id); obj != nil {
+		return &obj.Hyperlink
+	}
 	return cast[*widget.Hyperlink](dom.refs, id)
 }
 
@@ -96,6 +108,9 @@ func (dom *DOM) GetMarkdown(id string) *widget.RichText {
 }
 
 func (dom *DOM) GetSelect(id string) *widget.Select {
+	if obj := cast[*keySelect](dom.refs, id); obj != nil {
+		return &obj.Select
+	}
 	return cast[*widget.Select](dom.refs, id)
 }
 
@@ -108,6 +123,9 @@ func (dom *DOM) GetHr(id string) *widget.Separator {
 }
 
 func (dom *DOM) GetSlider(id string) *widget.Slider {
+	if obj := cast[*keySlider](dom.refs, id); obj != nil {
+		return &obj.Slider
+	}
 	return cast[*widget.Slider](dom.refs, id)
 }
 
@@ -119,9 +137,8 @@ func (dom *DOM) GetToolbar(id string) *widget.Toolbar {
 	return cast[*widget.Toolbar](dom.refs, id)
 }
 
-// TODO: make this work
-func (dom *DOM) GetToolbarAction(id string) *widget.ToolbarAction {
-	return cast[*widget.ToolbarAction](dom.refs, id)
+func (dom *DOM) GetToolbarAction(id string) *ToolbarAction {
+	return cast[*ToolbarAction](dom.refs, id)
 }
 
 func (dom *DOM) GetVirtualList(id string) *widget.List {
@@ -0,0 +1,354 @@
+package reago
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// keyEvents reports the raw key presses of a focusable widget, which fyne
+// only delivers to the focused widget, to bind:keydown and bind:keyup.
+type keyEvents struct {
+	dom       *DOM
+	pointer   pointerTrack
+	onKeyDown func(*fyne.KeyEvent)
+	onKeyUp   func(*fyne.KeyEvent)
+}
+
+func (keys *keyEvents) KeyDown(key *fyne.KeyEvent) {
+	if keys.onKeyDown != nil {
+		keys.onKeyDown(key)
+	}
+}
+
+func (keys *keyEvents) KeyUp(key *fyne.KeyEvent) {
+	if keys.onKeyUp != nil {
+		keys.onKeyUp(key)
+	}
+}
+
+func (keys *keyEvents) bindKeys(node *XMLNode, dom *DOM) {
+	keys.dom = dom
+	keys.onKeyDown = func(key *fyne.KeyEvent) {
+		fireEvent("keydown", node, dom, &Event{Key: key})
+	}
+	keys.onKeyUp = func(key *fyne.KeyEvent) {
+		fireEvent("keyup", node, dom, &Event{Key: key})
+	}
+}
+
+func (keys *keyEvents) ownerDOM() *DOM {
+	return keys.dom
+}
+
+// fireEvent dispatches the bind:<name> callback of node, if any.
+func fireEvent(name string, node *XMLNode, dom *DOM, event *Event) {
+	if bind := node.GetBind(name); bind != "" {
+		event.Type = name
+		event.Node = node
+		event.Modifiers = currentModifiers()
+		dom.dispatch(bind, event)
+	}
+}
+
+// inputEntry is the entry created by <input> and <textarea>. It reports focus
+// changes and raw key presses, and lets shortcuts of the window win over the
+// ones of the entry.
+type inputEntry struct {
+	widget.Entry
+	keyEvents
+
+	dom     *DOM
+	onFocus func()
+	onBlur  func()
+}
+
+func newInputEntry(multiLine bool, password bool) *inputEntry {
+	entry := &inputEntry{}
+	entry.MultiLine = multiLine
+	entry.Password = password
+	entry.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	entry.ExtendBaseWidget(entry)
+	if password {
+		entry.ActionItem = newPasswordRevealer(entry)
+	}
+	return entry
+}
+
+// passwordRevealer is the button showing or hiding the text of a password
+// entry, as in widget.NewPasswordEntry.
+type passwordRevealer struct {
+	widget.BaseWidget
+	icon  *canvas.Image
+	entry *inputEntry
+}
+
+func newPasswordRevealer(entry *inputEntry) *passwordRevealer {
+	revealer := &passwordRevealer{
+		icon:  canvas.NewImageFromResource(theme.VisibilityOffIcon()),
+		entry: entry,
+	}
+	revealer.ExtendBaseWidget(revealer)
+	return revealer
+}
+
+func (revealer *passwordRevealer) Tapped(*fyne.PointEvent) {
+	if revealer.entry.Disabled() {
+		return
+	}
+
+	revealer.entry.Password = !revealer.entry.Password
+	revealer.entry.Refresh()
+	revealer.Refresh()
+	if c := fyne.CurrentApp().Driver().CanvasForObject(revealer); c != nil {
+		c.Focus(revealer.entry)
+	}
+}
+
+func (revealer *passwordRevealer) Cursor() desktop.Cursor {
+	return desktop.DefaultCursor
+}
+
+func (revealer *passwordRevealer) Refresh() {
+	if revealer.entry.Password {
+		revealer.icon.Resource = theme.VisibilityOffIcon()
+	} else {
+		revealer.icon.Resource = theme.VisibilityIcon()
+	}
+	revealer.icon.Refresh()
+}
+
+func (revealer *passwordRevealer) MinSize() fyne.Size {
+	return fyne.NewSquareSize(theme.IconInlineSize())
+}
+
+func (revealer *passwordRevealer) CreateRenderer() fyne.WidgetRenderer {
+	return &passwordRevealerRenderer{WidgetRenderer: widget.NewSimpleRenderer(revealer.icon), icon: revealer.icon}
+}
+
+type passwordRevealerRenderer struct {
+	fyne.WidgetRenderer
+	icon *canvas.Image
+}
+
+// Layout centers the icon at its inline size.
+func (renderer *passwordRevealerRenderer) Layout(size fyne.Size) {
+	iconSize := theme.IconInlineSize()
+	renderer.icon.Resize(fyne.NewSquareSize(iconSize))
+	renderer.icon.Move(fyne.NewPos((size.Width-iconSize)/2, (size.Height-iconSize)/2))
+}
+
+func (entry *inputEntry) FocusGained() {
+	entry.Entry.FocusGained()
+	if entry.onFocus != nil {
+		entry.onFocus()
+	}
+}
+
+func (entry *inputEntry) FocusLost() {
+	entry.Entry.FocusLost()
+	if entry.onBlur != nil {
+		entry.onBlur()
+	}
+}
+
+func (entry *inputEntry) KeyDown(key *fyne.KeyEvent) {
+	entry.Entry.KeyDown(key)
+	entry.keyEvents.KeyDown(key)
+}
+
+func (entry *inputEntry) KeyUp(key *fyne.KeyEvent) {
+	entry.Entry.KeyUp(key)
+	entry.keyEvents.KeyUp(key)
+}
+
+// TypedShortcut runs the shortcut bound in the window, if any, as fyne sends
+// the shortcuts typed in the focused entry to the entry only.
+func (entry *inputEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if window := entry.window(); window != nil && window.triggerShortcut(shortcut.ShortcutName()) {
+		return
+	}
+	entry.Entry.TypedShortcut(shortcut)
+}
+
+func (entry *inputEntry) window() *Window {
+	if entry.dom != nil && entry.dom.window != nil {
+		return entry.dom.window
+	}
+	return windowOf(entry)
+}
+
+// bindEvents wires the bind:focus, bind:blur, bind:keydown and bind:keyup
+// callbacks of node to entry.
+func (entry *inputEntry) bindEvents(node *XMLNode, dom *DOM) {
+	entry.dom = dom
+	entry.onFocus = func() {
+		fireEvent("focus", node, dom, &Event{})
+	}
+	entry.onBlur = func() {
+		fireEvent("blur", node, dom, &Event{})
+	}
+	entry.bindKeys(node, dom)
+}
+
+// keyButton, keyCheck, keySelect, keySlider and keyHyperlink are the focusable
+// widgets of <button>, <checkbox>, <select>, <slider> and <a>/<link>, which
+// report raw key presses.
+type keyButton struct {
+	widget.Button
+	keyEvents
+}
+
+type keyCheck struct {
+	widget.Check
+	keyEvents
+}
+
+type keySelect struct {
+	widget.Select
+	keyEvents
+}
+
+type keySlider struct {
+	widget.Slider
+	keyEvents
+}
+
+type keyHyperlink struct {
+	widget.Hyperlink
+	keyEvents
+}
+
+// The focusable widgets report the pointer moving over them, so the hover
+// targets they are in see the pointer leaving through them.
+
+func (button *keyButton) MouseIn(event *desktop.MouseEvent) {
+	button.Button.MouseIn(event)
+	button.pointer.in(button, event)
+}
+
+func (button *keyButton) MouseMoved(event *desktop.MouseEvent) {
+	button.Button.MouseMoved(event)
+	button.pointer.moved(event)
+}
+
+func (button *keyButton) MouseOut() {
+	button.Button.MouseOut()
+	button.pointer.out()
+}
+
+func (check *keyCheck) MouseIn(event *desktop.MouseEvent) {
+	check.Check.MouseIn(event)
+	check.pointer.in(check, event)
+}
+
+func (check *keyCheck) MouseMoved(event *desktop.MouseEvent) {
+	check.Check.MouseMoved(event)
+	check.pointer.moved(event)
+}
+
+func (check *keyCheck) MouseOut() {
+	check.Check.MouseOut()
+	check.pointer.out()
+}
+
+func (sel *keySelect) MouseIn(event *desktop.MouseEvent) {
+	sel.Select.MouseIn(event)
+	sel.pointer.in(sel, event)
+}
+
+func (sel *keySelect) MouseMoved(event *desktop.MouseEvent) {
+	sel.Select.MouseMoved(event)
+	sel.pointer.moved(event)
+}
+
+func (sel *keySelect) MouseOut() {
+	sel.Select.MouseOut()
+	sel.pointer.out()
+}
+
+func (slider *keySlider) MouseIn(event *desktop.MouseEvent) {
+	slider.Slider.MouseIn(event)
+	slider.pointer.in(slider, event)
+}
+
+func (slider *keySlider) MouseMoved(event *desktop.MouseEvent) {
+	slider.Slider.MouseMoved(event)
+	slider.pointer.moved(event)
+}
+
+func (slider *keySlider) MouseOut() {
+	slider.Slider.MouseOut()
+	slider.pointer.out()
+}
+
+func (link *keyHyperlink) MouseIn(event *desktop.MouseEvent) {
+	link.Hyperlink.MouseIn(event)
+	link.pointer.in(link, event)
+}
+
+func (link *keyHyperlink) MouseMoved(event *desktop.MouseEvent) {
+	link.Hyperlink.MouseMoved(event)
+	link.pointer.moved(event)
+}
+
+func (link *keyHyperlink) MouseOut() {
+	link.Hyperlink.MouseOut()
+	link.pointer.out()
+}
+
+var (
+	_ desktop.Keyable = (*inputEntry)(nil)
+	_ desktop.Keyable = (*keyButton)(nil)
+	_ desktop.Keyable = (*keyCheck)(nil)
+	_ desktop.Keyable = (*keySelect)(nil)
+	_ desktop.Keyable = (*keySlider)(nil)
+	_ desktop.Keyable = (*keyHyperlink)(nil)
+)
+
+func newKeyButton(node *XMLNode, dom *DOM) *keyButton {
+	button := &keyButton{}
+	button.ExtendBaseWidget(button)
+	button.bindKeys(node, dom)
+	return button
+}
+
+func newKeyCheck(label string, node *XMLNode, dom *DOM) *keyCheck {
+	check := &keyCheck{}
+	check.Text = label
+	check.ExtendBaseWidget(check)
+	check.bindKeys(node, dom)
+	return check
+}
+
+func newKeySelect(node *XMLNode, dom *DOM) *keySelect {
+	// the placeholder of widget.NewSelect
+	placeholder := widget.NewSelect(nil, nil).PlaceHolder
+
+	sel := &keySelect{}
+	sel.PlaceHolder = placeholder
+	sel.ExtendBaseWidget(sel)
+	sel.bindKeys(node, dom)
+	return sel
+}
+
+func newKeySlider(min float64, max float64, node *XMLNode, dom *DOM) *keySlider {
+	slider := &keySlider{}
+	slider.Min = min
+	slider.Max = max
+	slider.Step = 1
+	slider.Orientation = widget.Horizontal
+	slider.ExtendBaseWidget(slider)
+	slider.bindKeys(node, dom)
+	return slider
+}
+
+func newKeyHyperlink(text string, node *XMLNode, dom *DOM) *keyHyperlink {
+	link := &keyHyperlink{}
+	link.Text = text
+	link.ExtendBaseWidget(link)
+	link.bindKeys(node, dom)
+	return link
+}
@@ -0,0 +1,45 @@
+package reago_test
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+func TestPasswordInputReveal(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.Template(`
+		<col>
+			<input id="password" type="password" />
+			<input id="name" />
+		</col>
+	`)
+	h.Mount(dom)
+
+	if dom.GetInput("name").ActionItem != nil {
+		t.Error("a text input got an action item")
+	}
+
+	entry := dom.GetInput("password")
+	if !entry.Password {
+		t.Fatal("the password input hides nothing")
+	}
+	reveal, ok := entry.ActionItem.(fyne.Tappable)
+	if !ok {
+		t.Fatal("the password input has no reveal button")
+	}
+
+	reveal.Tapped(&fyne.PointEvent{})
+	if entry.Password {
+		t.Error("the reveal button didn't show the password")
+	}
+	reveal.Tapped(&fyne.PointEvent{})
+	if !entry.Password {
+		t.Error("the reveal button didn't hide the password again")
+	}
+}
@@ -0,0 +1,369 @@
+package reago
+
+import (
+	"slices"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Event describes what triggered a callback registered with UseEventCallback.
+type Event struct {
+	// Type is the bound event name, e.g. "click" or "keydown".
+	Type string
+	// Node is the template node the callback is bound on.
+	Node *XMLNode
+	// Item is the data of the <list> or <tree> item the node belongs to.
+	Item any
+	// Position is relative to the object, AbsolutePosition to the window.
+	Position         fyne.Position
+	AbsolutePosition fyne.Position
+	// Modifiers are the keyboard modifiers held when the event happened.
+	Modifiers fyne.KeyModifier
+	// Key is set for keyboard events.
+	Key *fyne.KeyEvent
+	// Scroll is set for scroll events. Offset is the scroll position of a
+	// <scroll>.
+	Scroll fyne.Delta
+	Offset fyne.Position
+}
+
+// eventTarget wraps a parsed object so it can receive input events the
+// object doesn't handle itself. Events the wrapped object handles (e.g. a
+// tap on a button) still go to the object.
+//
+// fyne delivers events to the innermost object implementing the matching
+// interface, so each kind of event gets its own wrapper type and objects are
+// only wrapped for the events actually bound in the template.
+type eventTarget struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+	node    *XMLNode
+	dom     *DOM
+}
+
+func (target *eventTarget) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(target.content)
+}
+
+func (target *eventTarget) wrapped() fyne.CanvasObject {
+	return target.content
+}
+
+func (target *eventTarget) ownerDOM() *DOM {
+	return target.dom
+}
+
+// fire dispatches the bind:<name> callback of the node, if any.
+func (target *eventTarget) fire(name string, event *Event) {
+	bind := target.node.GetBind(name)
+	if bind == "" {
+		return
+	}
+
+	event.Type = name
+	event.Node = target.node
+	if event.Modifiers == 0 {
+		event.Modifiers = currentModifiers()
+	}
+	target.dom.dispatch(bind, event)
+}
+
+func (target *eventTarget) firePoint(name string, point *fyne.PointEvent) {
+	target.fire(name, &Event{Position: point.Position, AbsolutePosition: point.AbsolutePosition})
+}
+
+func currentModifiers() fyne.KeyModifier {
+	if driver, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		return driver.CurrentKeyModifiers()
+	}
+	return 0
+}
+
+type tapTarget struct{ *eventTarget }
+
+func (target *tapTarget) Tapped(event *fyne.PointEvent) {
+	target.firePoint("click", event)
+}
+
+type doubleTapTarget struct{ tapTarget }
+
+func (target *doubleTapTarget) DoubleTapped(event *fyne.PointEvent) {
+	target.firePoint("dblclick", event)
+}
+
+// focusTarget makes an object that can't be focused focusable, so it reports
+// focus changes and key presses. Tapping it focuses it.
+type focusTarget struct{ tapTarget }
+
+func (target *focusTarget) FocusGained() {
+	target.fire("focus", &Event{})
+}
+
+func (target *focusTarget) FocusLost() {
+	target.fire("blur", &Event{})
+}
+
+func (target *focusTarget) TypedRune(rune) {}
+
+func (target *focusTarget) TypedKey(*fyne.KeyEvent) {}
+
+func (target *focusTarget) KeyDown(key *fyne.KeyEvent) {
+	target.fire("keydown", &Event{Key: key})
+}
+
+func (target *focusTarget) KeyUp(key *fyne.KeyEvent) {
+	target.fire("keyup", &Event{Key: key})
+}
+
+type doubleFocusTarget struct{ focusTarget }
+
+func (target *doubleFocusTarget) DoubleTapped(event *fyne.PointEvent) {
+	target.firePoint("dblclick", event)
+}
+
+// hoverTarget reports the pointer entering and leaving the object. fyne
+// sends MouseOut when the pointer moves onto a hoverable child too, so the
+// target keeps hovered while the pointer, following its last move, is still
+// inside. The hoverable widgets of ReaGO then report where the pointer goes,
+// which gives the target its leave when the pointer leaves through them.
+type hoverTarget struct {
+	*eventTarget
+	pointer pointerTrack
+	inside  bool
+}
+
+func (target *hoverTarget) MouseIn(event *desktop.MouseEvent) {
+	target.pointer.in(target, event)
+	hovered.remove(target)
+	if target.inside {
+		// back from a child
+		return
+	}
+	target.inside = true
+	target.fire("hover", &Event{
+		Position:         event.Position,
+		AbsolutePosition: event.AbsolutePosition,
+		Modifiers:        event.Modifier,
+	})
+}
+
+func (target *hoverTarget) MouseMoved(event *desktop.MouseEvent) {
+	target.pointer.moved(event)
+}
+
+func (target *hoverTarget) MouseOut() {
+	next := target.pointer.next()
+	if containsPosition(target, next) {
+		// on a child, which reports where the pointer goes
+		hovered.add(target)
+		return
+	}
+	target.leave()
+	pointerLeft(next)
+}
+
+func (target *hoverTarget) leave() {
+	hovered.remove(target)
+	if target.inside {
+		target.inside = false
+		target.fire("leave", &Event{})
+	}
+}
+
+// hovered are the hover targets the pointer is on a hoverable child of.
+var hovered hoverTargets
+
+type hoverTargets struct {
+	lock    sync.Mutex
+	targets []*hoverTarget
+}
+
+func (targets *hoverTargets) add(target *hoverTarget) {
+	targets.lock.Lock()
+	defer targets.lock.Unlock()
+	if !slices.Contains(targets.targets, target) {
+		targets.targets = append(targets.targets, target)
+	}
+}
+
+func (targets *hoverTargets) remove(target *hoverTarget) {
+	targets.lock.Lock()
+	defer targets.lock.Unlock()
+	targets.targets = slices.DeleteFunc(targets.targets, func(other *hoverTarget) bool {
+		return other == target
+	})
+}
+
+// leave gives their leave to the targets matching left.
+func (targets *hoverTargets) leave(left func(*hoverTarget) bool) {
+	targets.lock.Lock()
+	var leaving []*hoverTarget
+	for _, target := range targets.targets {
+		if left(target) {
+			leaving = append(leaving, target)
+		}
+	}
+	targets.lock.Unlock()
+
+	for _, target := range leaving {
+		target.leave()
+	}
+}
+
+// pointerTrack follows the pointer over a hoverable object. fyne doesn't
+// tell where the pointer went on MouseOut, so it is guessed from the last
+// move.
+type pointerTrack struct {
+	last     fyne.Position
+	previous fyne.Position
+}
+
+// in reports the pointer entering obj, which the targets not containing obj
+// were left for.
+func (track *pointerTrack) in(obj fyne.CanvasObject, event *desktop.MouseEvent) {
+	track.last, track.previous = event.AbsolutePosition, event.AbsolutePosition
+	hovered.leave(func(target *hoverTarget) bool {
+		return target != obj && !containsObject(target, obj)
+	})
+}
+
+func (track *pointerTrack) moved(event *desktop.MouseEvent) {
+	track.previous, track.last = track.last, event.AbsolutePosition
+}
+
+// out reports the pointer leaving the object.
+func (track *pointerTrack) out() {
+	pointerLeft(track.next())
+}
+
+// next is the absolute position the pointer is guessed to move to.
+func (track *pointerTrack) next() fyne.Position {
+	return track.last.Add(track.last.Subtract(track.previous))
+}
+
+// pointerLeft gives their leave to the targets pos is out of.
+func pointerLeft(pos fyne.Position) {
+	hovered.leave(func(target *hoverTarget) bool {
+		return !containsPosition(target, pos)
+	})
+}
+
+// containsPosition reports whether the absolute position pos is inside obj.
+func containsPosition(obj fyne.CanvasObject, pos fyne.Position) bool {
+	if !obj.Visible() {
+		return false
+	}
+	topLeft := fyne.CurrentApp().Driver().AbsolutePositionForObject(obj)
+	size := obj.Size()
+	return pos.X >= topLeft.X && pos.Y >= topLeft.Y &&
+		pos.X < topLeft.X+size.Width && pos.Y < topLeft.Y+size.Height
+}
+
+type scrollTarget struct{ *eventTarget }
+
+func (target *scrollTarget) Scrolled(event *fyne.ScrollEvent) {
+	target.fire("scroll", &Event{
+		Position:         event.Position,
+		AbsolutePosition: event.AbsolutePosition,
+		Scroll:           event.Scrolled,
+	})
+}
+
+// contextMenuTarget shows a popup menu on right click, or long press on
+// mobile.
+type contextMenuTarget struct {
+	*eventTarget
+	onTappedSecondary func(*fyne.PointEvent)
+}
+
+func (target *contextMenuTarget) TappedSecondary(event *fyne.PointEvent) {
+	target.onTappedSecondary(event)
+}
+
+var (
+	_ fyne.Tappable          = (*tapTarget)(nil)
+	_ fyne.DoubleTappable    = (*doubleTapTarget)(nil)
+	_ fyne.Focusable         = (*focusTarget)(nil)
+	_ desktop.Keyable        = (*focusTarget)(nil)
+	_ fyne.DoubleTappable    = (*doubleFocusTarget)(nil)
+	_ desktop.Hoverable      = (*hoverTarget)(nil)
+	_ fyne.Scrollable        = (*scrollTarget)(nil)
+	_ fyne.SecondaryTappable = (*contextMenuTarget)(nil)
+)
+
+// wrapEvents wraps obj for every event bound on node that obj doesn't
+// already handle. menuNode is the <context-menu> child of node, if any.
+func wrapEvents(obj fyne.CanvasObject, node *XMLNode, menuNode *XMLNode, dom *DOM) fyne.CanvasObject {
+	wrap := func(content fyne.CanvasObject, create func(*eventTarget) fyne.Widget) fyne.CanvasObject {
+		base := &eventTarget{content: content, node: node, dom: dom}
+		wrapper := create(base)
+		base.ExtendBaseWidget(wrapper)
+		return wrapper
+	}
+
+	_, tappable := obj.(fyne.Tappable)
+	_, focusable := obj.(fyne.Focusable)
+	tap := node.HasBind("click") && !tappable
+	double := node.HasBind("dblclick")
+	focus := !focusable && (node.HasBind("focus") || node.HasBind("blur") || node.HasBind("keydown") || node.HasBind("keyup"))
+
+	switch {
+	case focus && double:
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget {
+			return &doubleFocusTarget{focusTarget{tapTarget{base}}}
+		})
+	case focus:
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget { return &focusTarget{tapTarget{base}} })
+	case double:
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget { return &doubleTapTarget{tapTarget{base}} })
+	case tap:
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget { return &tapTarget{base} })
+	}
+
+	if node.HasBind("hover") || node.HasBind("leave") {
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget { return &hoverTarget{eventTarget: base} })
+	}
+
+	if _, scrollable := obj.(fyne.Scrollable); node.HasBind("scroll") && !scrollable {
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget { return &scrollTarget{base} })
+	}
+
+	if menuNode != nil || node.HasBind("contextmenu") {
+		obj = wrap(obj, func(base *eventTarget) fyne.Widget {
+			target := &contextMenuTarget{eventTarget: base}
+			bindContextMenu(target, node, menuNode, dom)
+			return target
+		})
+	}
+
+	return obj
+}
+
+// bindContextMenu attaches the <context-menu> child, or the bind:contextmenu
+// menu or callback, of node to target.
+func bindContextMenu(target *contextMenuTarget, node *XMLNode, menuNode *XMLNode, dom *DOM) {
+	var menu *fyne.Menu
+	if menuNode != nil {
+		menu = parseContextMenu(menuNode, node, dom)
+	} else if bind := node.GetBind("contextmenu"); bind != "" {
+		menu = dom.menus[bind]
+	}
+
+	if menu == nil {
+		target.onTappedSecondary = func(event *fyne.PointEvent) {
+			target.firePoint("contextmenu", event)
+		}
+		return
+	}
+
+	target.onTappedSecondary = func(event *fyne.PointEvent) {
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(target)
+		if canvas == nil {
+			return
+		}
+		widget.ShowPopUpMenuAtPosition(menu, canvas, event.AbsolutePosition)
+	}
+}
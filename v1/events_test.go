@@ -0,0 +1,76 @@
+package reago
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestHoverLeavesThroughChildAtTheEdge(t *testing.T) {
+	test.NewTempApp(t)
+
+	dom := NewDOM()
+	var hovers, leaves int
+	dom.UseCallback("hover", func(node *XMLNode) { hovers++ })
+	dom.UseCallback("leave", func(node *XMLNode) { leaves++ })
+	dom.Template(`
+		<col>
+			<row bind:hover="hover" bind:leave="leave">
+				<button id="a">A</button>
+				<button id="b">B</button>
+			</row>
+			<button id="outside">Outside</button>
+		</col>
+	`)
+	test.NewTempWindow(t, dom.root).Resize(fyne.NewSize(400, 200))
+
+	var target *hoverTarget
+	for _, obj := range test.LaidOutObjects(dom.root) {
+		if hover, ok := obj.(*hoverTarget); ok {
+			target = hover
+		}
+	}
+	if target == nil {
+		t.Fatal("the row isn't hoverable")
+	}
+	a, b := dom.refs["a"].(*keyButton), dom.refs["b"].(*keyButton)
+
+	driver := fyne.CurrentApp().Driver()
+	at := func(obj fyne.CanvasObject, x float32) *desktop.MouseEvent {
+		pos := driver.AbsolutePositionForObject(obj).AddXY(x, obj.Size().Height/2)
+		return &desktop.MouseEvent{PointEvent: fyne.PointEvent{AbsolutePosition: pos}}
+	}
+	expect := func(step string, expectedHovers, expectedLeaves int) {
+		t.Helper()
+		if hovers != expectedHovers || leaves != expectedLeaves {
+			t.Fatalf("%s: %d hovers and %d leaves, expected %d and %d", step, hovers, leaves, expectedHovers, expectedLeaves)
+		}
+	}
+
+	// in from the right, then left over b and a
+	target.MouseIn(at(target, target.Size().Width-2))
+	target.MouseMoved(at(target, target.Size().Width-10))
+	target.MouseOut()
+	b.MouseIn(at(b, b.Size().Width-2))
+	b.MouseMoved(at(b, 2))
+	b.MouseOut()
+	a.MouseIn(at(a, a.Size().Width-2))
+	expect("moving over the children", 1, 0)
+
+	// out through a, which touches the left edge
+	a.MouseMoved(at(a, 2))
+	a.MouseOut()
+	expect("leaving through a child", 1, 1)
+
+	// back in through b, then straight onto another hoverable
+	target.MouseIn(at(b, 2))
+	target.MouseOut()
+	b.MouseIn(at(b, 2))
+	expect("entering again", 2, 1)
+	outside := dom.refs["outside"].(*keyButton)
+	b.MouseOut()
+	outside.MouseIn(at(outside, 2))
+	expect("entering another widget", 2, 2)
+}
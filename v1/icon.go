@@ -0,0 +1,190 @@
+package reago
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+type iIcons struct {
+	lock      sync.RWMutex
+	resources map[string]fyne.Resource
+	files     []fs.FS
+	cache     map[string]fyne.Resource
+	colored   map[string]bool
+
+	// disk maps the absolute paths of the files read from the OS file system
+	// to their names, watched by the watchers of hot reloading DOMs
+	disk     map[string]string
+	watchers []*Watcher
+}
+
+// Icons is the registry used by every tag that accepts an icon or image
+// source. Names are resolved in order against resources registered with
+// Register, fyne theme icon names, file systems registered with RegisterFS
+// and finally the OS file system.
+var Icons = iIcons{
+	resources: make(map[string]fyne.Resource),
+	cache:     make(map[string]fyne.Resource),
+	colored:   make(map[string]bool),
+	disk:      make(map[string]string),
+}
+
+func (icons *iIcons) Register(name string, resource fyne.Resource) {
+	icons.lock.Lock()
+	defer icons.lock.Unlock()
+	icons.resources[name] = resource
+}
+
+// RegisterFS adds a file system (usually an embed.FS) that icon and image
+// paths are looked up in before falling back to the OS file system.
+func (icons *iIcons) RegisterFS(fsys fs.FS) {
+	icons.lock.Lock()
+	defer icons.lock.Unlock()
+	icons.files = append(icons.files, fsys)
+}
+
+// Resolve returns the resource for name as is, or nil if it can't be found.
+func (icons *iIcons) Resolve(name string) fyne.Resource {
+	if name == "" {
+		return nil
+	}
+
+	icons.lock.RLock()
+	res, ok := icons.resources[name]
+	if !ok {
+		res, ok = icons.cache[name]
+	}
+	files := icons.files
+	icons.lock.RUnlock()
+
+	if ok {
+		return res
+	}
+
+	// theme.Icon returns a fallback icon for unknown names, the theme nil
+	if res := theme.Current().Icon(fyne.ThemeIconName(name)); res != nil {
+		return res
+	}
+
+	for _, fsys := range files {
+		if bytes, err := fs.ReadFile(fsys, strings.TrimPrefix(path.Clean(name), "/")); err == nil {
+			res = fyne.NewStaticResource(path.Base(name), bytes)
+			break
+		}
+	}
+
+	var diskPath string
+	if res == nil {
+		bytes, err := os.ReadFile(name)
+		if err != nil {
+			return nil
+		}
+		res = fyne.NewStaticResource(path.Base(name), bytes)
+		diskPath, _ = filepath.Abs(name)
+	}
+
+	icons.lock.Lock()
+	icons.cache[name] = res
+	var watchers []*Watcher
+	if diskPath != "" {
+		icons.disk[diskPath] = name
+		watchers = icons.watchers
+	}
+	icons.lock.Unlock()
+
+	for _, watcher := range watchers {
+		if err := watcher.add(diskPath); err != nil {
+			log.Println("Watcher error:", err)
+		}
+	}
+
+	return res
+}
+
+// Icon resolves name like Resolve, but SVG resources are recolored to match
+// the current theme foreground color, unless their name is passed to
+// KeepColors.
+func (icons *iIcons) Icon(name string) fyne.Resource {
+	res := icons.Resolve(name)
+	if res == nil {
+		return nil
+	}
+
+	icons.lock.RLock()
+	colored := icons.colored[name]
+	icons.lock.RUnlock()
+	if colored {
+		return res
+	}
+
+	if _, themed := res.(*theme.ThemedResource); !themed && strings.HasSuffix(strings.ToLower(res.Name()), ".svg") {
+		return theme.NewThemedResource(res)
+	}
+	return res
+}
+
+// KeepColors makes Icon return the SVGs named as they are, e.g. logos, instead
+// of recoloring them.
+func (icons *iIcons) KeepColors(names ...string) {
+	icons.lock.Lock()
+	defer icons.lock.Unlock()
+	for _, name := range names {
+		icons.colored[name] = true
+	}
+}
+
+// Clear drops every cached file resource, so they are read again on next use.
+func (icons *iIcons) Clear() {
+	icons.lock.Lock()
+	defer icons.lock.Unlock()
+	icons.cache = make(map[string]fyne.Resource)
+	icons.disk = make(map[string]string)
+}
+
+// forget drops the cached resource read from the file at the absolute path
+// filename, and reports whether there was one.
+func (icons *iIcons) forget(filename string) bool {
+	icons.lock.Lock()
+	defer icons.lock.Unlock()
+
+	name, ok := icons.disk[filename]
+	if ok {
+		delete(icons.cache, name)
+	}
+	return ok
+}
+
+// watch makes watcher watch the files read from the OS file system, until it
+// is stopped.
+func (icons *iIcons) watch(watcher *Watcher) {
+	icons.lock.Lock()
+	icons.watchers = append(icons.watchers, watcher)
+	var files []string
+	for file := range icons.disk {
+		files = append(files, file)
+	}
+	icons.lock.Unlock()
+
+	for _, file := range files {
+		if err := watcher.add(file); err != nil {
+			log.Println("Watcher error:", err)
+		}
+	}
+}
+
+func (icons *iIcons) unwatch(watcher *Watcher) {
+	icons.lock.Lock()
+	defer icons.lock.Unlock()
+	icons.watchers = slices.DeleteFunc(slices.Clone(icons.watchers), func(other *Watcher) bool {
+		return other == watcher
+	})
+}
@@ -0,0 +1,135 @@
+package reago
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+const testSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M0 0h24v24H0z"/></svg>`
+
+func TestIconsResolveThemeName(t *testing.T) {
+	res := Icons.Resolve(string(theme.IconNameHome))
+	if res == nil || res.Name() != theme.HomeIcon().Name() {
+		t.Fatalf("Resolve(home) = %v, want the theme home icon", res)
+	}
+}
+
+func TestIconsResolveFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "save.svg")
+	if err := os.WriteFile(file, []byte(testSVG), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Icons.Clear)
+
+	res := Icons.Resolve(file)
+	if res == nil {
+		t.Fatalf("Resolve(%s) = nil", file)
+	}
+	if res.Name() != "save.svg" || string(res.Content()) != testSVG {
+		t.Errorf("Resolve(%s) = %s %q, want the file content", file, res.Name(), res.Content())
+	}
+
+	if icon := Icons.Icon(file); icon == nil {
+		t.Errorf("Icon(%s) = nil", file)
+	} else if _, themed := icon.(*theme.ThemedResource); !themed {
+		t.Errorf("Icon(%s) = %T, want a themed SVG", file, icon)
+	}
+}
+
+func TestIconsResolveFS(t *testing.T) {
+	Icons.RegisterFS(fstest.MapFS{
+		"icons/reago-test-open.svg": {Data: []byte(testSVG)},
+	})
+	t.Cleanup(func() {
+		Icons.lock.Lock()
+		Icons.files = Icons.files[:len(Icons.files)-1]
+		Icons.lock.Unlock()
+		Icons.Clear()
+	})
+
+	for _, name := range []string{"icons/reago-test-open.svg", "/icons/reago-test-open.svg"} {
+		res := Icons.Resolve(name)
+		if res == nil {
+			t.Errorf("Resolve(%s) = nil", name)
+			continue
+		}
+		if res.Name() != "reago-test-open.svg" || string(res.Content()) != testSVG {
+			t.Errorf("Resolve(%s) = %s %q, want the FS file", name, res.Name(), res.Content())
+		}
+	}
+}
+
+func TestIconsResolveMissing(t *testing.T) {
+	if res := Icons.Resolve("reago-test-missing.svg"); res != nil {
+		t.Errorf("Resolve(missing) = %v, want nil", res)
+	}
+}
+
+func TestIconsResolveRegistered(t *testing.T) {
+	res := fyne.NewStaticResource("registered.svg", []byte(testSVG))
+	Icons.Register("reago-test-registered", res)
+	t.Cleanup(func() {
+		Icons.lock.Lock()
+		delete(Icons.resources, "reago-test-registered")
+		Icons.lock.Unlock()
+	})
+
+	if got := Icons.Resolve("reago-test-registered"); got != res {
+		t.Errorf("Resolve(registered) = %v, want %v", got, res)
+	}
+}
+
+func TestIconsKeepColors(t *testing.T) {
+	res := fyne.NewStaticResource("logo.svg", []byte(testSVG))
+	Icons.Register("reago-test-logo", res)
+	Icons.KeepColors("reago-test-logo")
+	t.Cleanup(func() {
+		Icons.lock.Lock()
+		delete(Icons.resources, "reago-test-logo")
+		delete(Icons.colored, "reago-test-logo")
+		Icons.lock.Unlock()
+	})
+
+	if got := Icons.Icon("reago-test-logo"); got != res {
+		t.Errorf("Icon(logo) = %T, want the SVG as registered", got)
+	}
+}
+
+func TestIconsWatchedFilesReadAgain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "save.svg")
+	if err := os.WriteFile(file, []byte(testSVG), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Icons.Clear)
+
+	watcher, err := newWatcher(func(string) {})
+	if err != nil {
+		t.Skip(err)
+	}
+	defer watcher.Stop()
+
+	Icons.Resolve(file)
+	abs, _ := filepath.Abs(file)
+	watcher.lock.Lock()
+	watched := watcher.files[abs]
+	watcher.lock.Unlock()
+	if !watched {
+		t.Errorf("%s isn't watched", file)
+	}
+
+	changed := `<svg xmlns="http://www.w3.org/2000/svg"/>`
+	if err := os.WriteFile(file, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !Icons.forget(abs) {
+		t.Fatalf("%s isn't known as an icon file", file)
+	}
+	if res := Icons.Resolve(file); string(res.Content()) != changed {
+		t.Errorf("Resolve(%s) = %q after a change, want the new content", file, res.Content())
+	}
+}
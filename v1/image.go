@@ -0,0 +1,222 @@
+package reago
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+)
+
+type imageCache struct {
+	lock   sync.RWMutex
+	images map[string]image.Image
+}
+
+var images = imageCache{images: make(map[string]image.Image)}
+
+func (cache *imageCache) get(key string) (image.Image, bool) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	img, ok := cache.images[key]
+	return img, ok
+}
+
+func (cache *imageCache) set(key string, img image.Image) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.images[key] = img
+}
+
+// ClearImageCache drops every decoded image, so sources are loaded again.
+func ClearImageCache() {
+	images.lock.Lock()
+	defer images.lock.Unlock()
+	images.images = make(map[string]image.Image)
+}
+
+// imageLoader loads the sources of a single <img>, decoding them in the
+// background. Only the result of the latest request is displayed.
+type imageLoader struct {
+	obj         *canvas.Image
+	dom         *DOM
+	placeholder fyne.Resource
+	broken      fyne.Resource
+	generation  atomic.Uint64
+}
+
+func newImageLoader(obj *canvas.Image, dom *DOM) *imageLoader {
+	return &imageLoader{
+		obj:         obj,
+		dom:         dom,
+		placeholder: theme.FileImageIcon(),
+		broken:      theme.BrokenImageIcon(),
+	}
+}
+
+func (loader *imageLoader) LoadSrc(src string) {
+	if src == "" {
+		loader.show(nil, nil)
+		return
+	}
+	loader.load("src:"+src, func() (string, []byte, error) {
+		return loadSource(loader.dom, src)
+	})
+}
+
+func (loader *imageLoader) LoadBytes(data []byte) {
+	if len(data) == 0 {
+		loader.show(nil, nil)
+		return
+	}
+	sum := sha256.Sum256(data)
+	loader.load("bytes:"+base64.RawStdEncoding.EncodeToString(sum[:]), func() (string, []byte, error) {
+		return "", data, nil
+	})
+}
+
+func (loader *imageLoader) LoadURI(uri fyne.URI) {
+	if uri == nil {
+		loader.show(nil, nil)
+		return
+	}
+	loader.load("uri:"+uri.String(), func() (string, []byte, error) {
+		reader, err := storage.Reader(uri)
+		if err != nil {
+			return "", nil, err
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		return uri.Name(), data, err
+	})
+}
+
+func (loader *imageLoader) load(key string, read func() (string, []byte, error)) {
+	generation := loader.generation.Add(1)
+
+	if img, ok := images.get(key); ok {
+		loader.show(nil, img)
+		return
+	}
+
+	loader.show(loader.placeholder, nil)
+
+	go func() {
+		name, data, err := read()
+		if err != nil {
+			fyne.LogError("Failed to load image", err)
+			loader.showIfCurrent(generation, loader.broken, nil)
+			return
+		}
+
+		// SVGs are rendered by the canvas itself at the displayed size.
+		if isSVG(name, data) {
+			if !strings.HasSuffix(strings.ToLower(name), ".svg") {
+				name += ".svg"
+			}
+			loader.showIfCurrent(generation, fyne.NewStaticResource(path.Base(name), data), nil)
+			return
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			fyne.LogError("Failed to decode image", err)
+			loader.showIfCurrent(generation, loader.broken, nil)
+			return
+		}
+
+		images.set(key, img)
+		loader.showIfCurrent(generation, nil, img)
+	}()
+}
+
+func (loader *imageLoader) showIfCurrent(generation uint64, res fyne.Resource, img image.Image) {
+	if loader.generation.Load() == generation {
+		loader.show(res, img)
+	}
+}
+
+func (loader *imageLoader) show(res fyne.Resource, img image.Image) {
+	loader.obj.File = ""
+	loader.obj.Resource = res
+	loader.obj.Image = img
+	loader.obj.Refresh()
+}
+
+// loadSource reads an image source, which can be a data URI, a file in the
+// FS or dev dir of the DOM, in one of its assets, a path on disk or a
+// resource known by Icons.
+func loadSource(dom *DOM, src string) (string, []byte, error) {
+	if strings.HasPrefix(src, "data:") {
+		return decodeDataURI(src)
+	}
+
+	if dom.files != nil || dom.devDir != "" {
+		if data, err := dom.readFile(src); err == nil {
+			return src, data, nil
+		}
+	}
+
+	name := strings.TrimPrefix(path.Clean(src), "/")
+	for _, fsys := range dom.assets {
+		if data, err := fs.ReadFile(fsys, name); err == nil {
+			return src, data, nil
+		}
+	}
+
+	if data, err := os.ReadFile(src); err == nil {
+		return src, data, nil
+	}
+
+	res := Icons.Resolve(src)
+	if res == nil {
+		return "", nil, errors.New("image not found: " + src)
+	}
+	return res.Name(), res.Content(), nil
+}
+
+func decodeDataURI(uri string) (string, []byte, error) {
+	meta, data, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return "", nil, errors.New("invalid data URI")
+	}
+
+	name := "data"
+	if strings.HasPrefix(meta, "image/svg") {
+		name = "data.svg"
+	}
+
+	if strings.HasSuffix(meta, ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		return name, decoded, err
+	}
+
+	decoded, err := url.PathUnescape(data)
+	return name, []byte(decoded), err
+}
+
+func isSVG(name string, data []byte) bool {
+	if strings.HasSuffix(strings.ToLower(name), ".svg") {
+		return true
+	}
+	head := bytes.TrimSpace(data[:min(len(data), 256)])
+	return bytes.HasPrefix(head, []byte("<svg")) ||
+		(bytes.HasPrefix(head, []byte("<?xml")) && bytes.Contains(head, []byte("<svg")))
+}
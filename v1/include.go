@@ -0,0 +1,212 @@
+package reago
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// includeView is a mounted <include> tag.
+type includeView struct {
+	dom      *DOM
+	node     *XMLNode
+	obj      *fyne.Container
+	file     string
+	parent   *includeView
+	children []*includeView
+	scope    *elementScope
+	removed  bool
+}
+
+// mountInclude creates the view of an <include src="file"> tag. The file is
+// resolved relative to the including one, or to the root of the file system
+// of the DOM when it starts with "/".
+func mountInclude(node *XMLNode, dom *DOM) fyne.CanvasObject {
+	view := &includeView{
+		dom:    dom,
+		node:   node,
+		obj:    container.NewStack(),
+		parent: dom.includeView,
+	}
+
+	base := dom.file
+	if view.parent != nil {
+		base = view.parent.file
+		// the items of a <list> or <tree> inside the parent are rendered in
+		// clones, which don't outlive their render
+		if view.parent.dom == dom {
+			view.parent.children = append(view.parent.children, view)
+		}
+	}
+	view.file = resolveInclude(base, node.GetAttr("src"))
+
+	dom.includes = append(dom.includes, view)
+	view.render()
+
+	return view.obj
+}
+
+func resolveInclude(base string, src string) string {
+	if strings.HasPrefix(src, "/") || base == "" {
+		return src
+	}
+	return path.Join(path.Dir(base), src)
+}
+
+func (view *includeView) render() {
+	dom := view.dom
+
+	removeIncludes(view.children)
+	view.children = nil
+
+	kept := dom.includes[:0]
+	for _, include := range dom.includes {
+		if !include.removed {
+			kept = append(kept, include)
+		}
+	}
+	dom.includes = kept
+
+	var obj fyne.CanvasObject
+	content, err := view.read()
+	if err != nil {
+		log.Println(err)
+		obj = widget.NewLabelWithStyle(
+			"include_error: "+err.Error(),
+			fyne.TextAlignCenter,
+			fyne.TextStyle{Monospace: true},
+		)
+	}
+
+	previous := dom.includeView
+	dom.includeView = view
+	dom.renderScope(&view.scope, func() {
+		if err == nil {
+			obj = Parser.ParseXML(content, dom)
+		}
+	})
+	dom.includeView = previous
+
+	view.obj.Objects = []fyne.CanvasObject{obj}
+	view.obj.Refresh()
+}
+
+// read returns the content of the file, with the attributes of the tag
+// passed as {{props.name}}.
+func (view *includeView) read() (string, error) {
+	if view.node.GetAttr("src") == "" {
+		return "", errors.New("include without src")
+	}
+
+	chain := []string{view.file}
+	for parent := view.parent; parent != nil; parent = parent.parent {
+		chain = append([]string{parent.file}, chain...)
+	}
+	if view.dom.file != "" {
+		chain = append([]string{view.dom.file}, chain...)
+	}
+	key := view.dom.fileKey(view.file)
+	for i, file := range chain[:len(chain)-1] {
+		if view.dom.fileKey(file) == key {
+			return "", errors.New("include cycle: " + strings.Join(chain[i:], " -> "))
+		}
+	}
+
+	view.dom.watchFile(view.file)
+
+	content, err := view.dom.readInclude(view.file)
+	if err != nil {
+		return "", err
+	}
+	return substituteProps(content, view.node), nil
+}
+
+// includeCache holds the content of the included files, shared by a DOM and
+// its clones, so that the items of a <list> or <tree> don't read their
+// includes again on every update.
+type includeCache struct {
+	lock  sync.Mutex
+	files map[string]cachedInclude
+}
+
+type cachedInclude struct {
+	file    string
+	content string
+	// inFragment tells whether a clone rendered the file
+	inFragment bool
+}
+
+// readInclude returns the content of an included file, read once until it
+// changes.
+func (dom *DOM) readInclude(file string) (string, error) {
+	key := dom.fileKey(file)
+	cache := dom.includeCache
+
+	cache.lock.Lock()
+	cached, ok := cache.files[key]
+	cache.lock.Unlock()
+
+	if !ok {
+		content, err := dom.readFile(file)
+		if err != nil {
+			return "", err
+		}
+		cached.file, cached.content = file, string(content)
+	}
+	cached.inFragment = cached.inFragment || dom.fragment
+
+	cache.lock.Lock()
+	cache.files[key] = cached
+	cache.lock.Unlock()
+
+	return cached.content, nil
+}
+
+// forget drops the content of the file with key. It returns the name of the
+// file if a clone rendered it.
+func (cache *includeCache) forget(key string) (string, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cached, ok := cache.files[key]
+	delete(cache.files, key)
+	return cached.file, ok && cached.inFragment
+}
+
+func (cache *includeCache) has(key string) bool {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	_, ok := cache.files[key]
+	return ok
+}
+
+var propPattern = regexp.MustCompile(`\{\{\s*props\.([^{}\s]+)\s*\}\}`)
+
+// substituteProps replaces {{props.name}} with the name attribute of node,
+// escaped for XML, or with nothing when it has none.
+func substituteProps(content string, node *XMLNode) string {
+	return propPattern.ReplaceAllStringFunc(content, func(match string) string {
+		name := propPattern.FindStringSubmatch(match)[1]
+
+		var buf bytes.Buffer
+		xml.EscapeText(&buf, []byte(node.GetAttr(name)))
+		return buf.String()
+	})
+}
+
+func removeIncludes(views []*includeView) {
+	for _, view := range views {
+		view.removed = true
+		removeIncludes(view.children)
+	}
+}
@@ -0,0 +1,186 @@
+package reago_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+func TestInclude(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseFS(fstest.MapFS{
+		"main.xml": {Data: []byte(`
+			<col>
+				<include src="parts/header.xml" title="Settings &amp; more" />
+				<include src="/parts/footer.xml" />
+			</col>
+		`)},
+		"parts/header.xml": {Data: []byte(`
+			<row>
+				<label id="title">{{props.title}}</label>
+				<include src="../user.xml" />
+			</row>
+		`)},
+		"parts/footer.xml": {Data: []byte(`<label id="footer">Footer{{props.missing}}</label>`)},
+		"user.xml":         {Data: []byte(`<label id="user">Ana</label>`)},
+	})
+	if _, err := dom.FileTemplate("main.xml", false); err != nil {
+		t.Fatal(err)
+	}
+	h.Mount(dom)
+
+	h.AssertText("#title", "Settings & more")
+	h.AssertText("#user", "Ana")
+	h.AssertText("#footer", "Footer")
+}
+
+func TestIncludeErrors(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseFS(fstest.MapFS{
+		"main.xml": {Data: []byte(`
+			<col>
+				<include id="cycle" src="a.xml" />
+				<include id="missing" src="missing.xml" />
+			</col>
+		`)},
+		"a.xml": {Data: []byte(`<include src="b.xml" />`)},
+		"b.xml": {Data: []byte(`<include src="a.xml" />`)},
+	})
+	if _, err := dom.FileTemplate("main.xml", false); err != nil {
+		t.Fatal(err)
+	}
+	h.Mount(dom)
+
+	if text := h.Text("#cycle"); !strings.Contains(text, "include cycle: a.xml -> b.xml -> a.xml") {
+		t.Errorf("cycle shown as %q", text)
+	}
+	if text := h.Text("#missing"); !strings.Contains(text, "include_error") {
+		t.Errorf("missing file shown as %q", text)
+	}
+}
+
+func TestFileTemplateErrors(t *testing.T) {
+	reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseFS(fstest.MapFS{
+		"broken.xml": {Data: []byte(`<col><label>a</col>`)},
+	})
+
+	if _, err := dom.FileTemplate("missing.xml", false); err == nil {
+		t.Error("missing file rendered without error")
+	}
+	if _, err := dom.FileTemplate("broken.xml", false); err == nil {
+		t.Error("invalid template rendered without error")
+	}
+}
+
+// countingFS counts the times each file is read.
+type countingFS struct {
+	fstest.MapFS
+	read map[string]int
+}
+
+func (fsys *countingFS) ReadFile(name string) ([]byte, error) {
+	fsys.read[name]++
+	return fsys.MapFS.ReadFile(name)
+}
+
+func TestIncludeInListItems(t *testing.T) {
+	h := reagotest.New(t)
+
+	files := &countingFS{
+		MapFS: fstest.MapFS{
+			"main.xml": {Data: []byte(`<include src="parts/users.xml" />`)},
+			"parts/users.xml": {Data: []byte(`
+				<list id="users" bind:items="users">
+					<include src="user.xml" />
+				</list>
+			`)},
+			"parts/user.xml": {Data: []byte(`<label bind:content="">User {{Name}}</label>`)},
+		},
+		read: map[string]int{},
+	}
+
+	dom := reago.NewDOM()
+	users := dom.UseState().List("users", []any{user{ID: 1, Name: "Ana"}})
+	dom.UseFS(files)
+	if _, err := dom.FileTemplate("main.xml", false); err != nil {
+		t.Fatal(err)
+	}
+	h.Mount(dom)
+
+	h.AssertText("#users", "User Ana")
+
+	users.Append(user{ID: 2, Name: "Bob"})
+	h.AssertText("#users", "User Ana User Bob")
+
+	if read := files.read["parts/user.xml"]; read != 1 {
+		t.Errorf("the item include was read %d times, expected once", read)
+	}
+}
+
+func TestWatchIncludeInListItems(t *testing.T) {
+	skipWithoutWatcher(t)
+	h := reagotest.New(t)
+
+	dir := t.TempDir()
+	write := func(name string, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.xml", `<list id="users" bind:items="users"><include src="user.xml" /></list>`)
+	write("user.xml", `<label bind:content="">User {{Name}}</label>`)
+
+	dom := reago.NewDOM()
+	dom.UseState().List("users", []any{user{ID: 1, Name: "Ana"}})
+	watcher, err := dom.FileTemplate(filepath.Join(dir, "main.xml"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+	h.Mount(dom)
+
+	h.AssertText("#users", "User Ana")
+
+	write("user.xml", `<label bind:content="">Member {{Name}}</label>`)
+
+	waitReload(h, func() bool { return h.Text("#users") == "Member Ana" })
+	h.AssertText("#users", "Member Ana")
+}
+
+func TestIncludeInModal(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseFS(fstest.MapFS{
+		"parts/main.xml":   {Data: []byte(`<label>Main</label>`)},
+		"parts/dialog.xml": {Data: []byte(`<label id="dialog">Dialog</label>`)},
+	})
+	if _, err := dom.FileTemplate("parts/main.xml", false); err != nil {
+		t.Fatal(err)
+	}
+	h.Mount(dom)
+
+	modal := h.Window.Modal(`<include src="dialog.xml" />`, reago.ModalOptions{}, nil)
+	defer modal.Close()
+
+	element, err := modal.GetDOM().Query("#dialog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if element == nil {
+		t.Fatal("the include of the modal wasn't resolved relative to the file of the DOM")
+	}
+}
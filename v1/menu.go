@@ -1,8 +1,10 @@
 package reago
 
 import (
+	"errors"
+	"log"
+
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/theme"
 )
 
 func MenuGroup(label string, items ...*fyne.MenuItem) *fyne.Menu {
@@ -14,7 +16,7 @@ func MenuItem(label string, action func()) *fyne.MenuItem {
 }
 
 func MenuItemWithIcon(label string, icon string, action func()) *fyne.MenuItem {
-	res := theme.Icon(fyne.ThemeIconName(icon))
+	res := Icons.Icon(icon)
 	if res == nil {
 		return MenuItem(label, action)
 	}
@@ -28,3 +30,161 @@ func MenuItemWithIcon(label string, icon string, action func()) *fyne.MenuItem {
 func MenuItemSeparator() *fyne.MenuItem {
 	return fyne.NewMenuItemSeparator()
 }
+
+// MenuTemplate parses a <menubar> template, or a single <menu>, into menus
+// bound to the DOM state and callbacks. Items with an id are registered and
+// can be retrieved with GetMenuItem.
+func (dom *DOM) MenuTemplate(content string) ([]*fyne.Menu, error) {
+	root, err := Parser.parseRoot(content, dom.parseMode)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root.GetTag() {
+	case "menubar":
+		var menus []*fyne.Menu
+		for i := range root.Nodes {
+			if root.Nodes[i].GetTag() == "menu" {
+				menus = append(menus, parseMenu(&root.Nodes[i], dom, nil, nil))
+			}
+		}
+		return menus, nil
+	case "menu":
+		return []*fyne.Menu{parseMenu(root, dom, nil, nil)}, nil
+	}
+
+	return nil, errors.New("menu template must have a <menubar> or <menu> root")
+}
+
+func (dom *DOM) GetMenuItem(id string) *fyne.MenuItem {
+	return dom.menuRefs[id]
+}
+
+// parseMenu builds a menu from a <menu> node. refresh is called whenever a
+// bound value changes; when nil, the menu is top-level and refreshes itself.
+// Item callbacks receive origin, when given, instead of the item node.
+func parseMenu(node *XMLNode, dom *DOM, origin *XMLNode, refresh func()) *fyne.Menu {
+	var top *fyne.Menu
+	if refresh == nil {
+		refresh = func() {
+			if top != nil {
+				top.Refresh()
+			}
+		}
+	}
+
+	menu := fyne.NewMenu(node.GetAttr("label"))
+
+	node.BindString("label", dom, func(value string) {
+		menu.Label = value
+		refresh()
+	})
+
+	menu.Items = parseMenuItems(node, dom, origin, refresh)
+
+	top = menu
+	return menu
+}
+
+func parseMenuItems(node *XMLNode, dom *DOM, origin *XMLNode, refresh func()) []*fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for i := range node.Nodes {
+		if item := parseMenuItem(&node.Nodes[i], dom, origin, refresh); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseMenuItem(node *XMLNode, dom *DOM, origin *XMLNode, refresh func()) *fyne.MenuItem {
+	var item *fyne.MenuItem
+
+	switch node.GetTag() {
+	case "separator", "hr":
+		return fyne.NewMenuItemSeparator()
+	case "menu":
+		item = fyne.NewMenuItem(node.GetAttr("label"), nil)
+		item.ChildMenu = fyne.NewMenu(item.Label, parseMenuItems(node, dom, origin, refresh)...)
+		node.BindString("label", dom, func(value string) {
+			item.Label = value
+			item.ChildMenu.Label = value
+			refresh()
+		})
+	case "item":
+		item = fyne.NewMenuItem("", nil)
+
+		if node.HasAttr("label") || node.HasBind("label") {
+			node.BindString("label", dom, func(value string) {
+				item.Label = value
+				refresh()
+			})
+		} else {
+			node.BindContent(dom, func(value string) {
+				item.Label = value
+				refresh()
+			})
+		}
+
+		setChecked := node.BindBool("checked", dom, func(value bool) {
+			item.Checked = value
+			refresh()
+		})
+
+		onClick := node.BindCallback("click", dom)
+		if bind := node.GetBind("click"); bind != "" {
+			if origin != nil {
+				onClick = func() {
+					dom.invoke(bind, origin)
+				}
+			} else {
+				dom.menuActions[bind] = append(dom.menuActions[bind], item)
+			}
+		}
+		item.Action = func() {
+			if setChecked != nil {
+				setChecked(!item.Checked)
+			}
+			if onClick != nil {
+				onClick()
+			}
+		}
+
+		item.IsQuit = node.GetAttrBool("quit")
+
+		if children := parseMenuItems(node, dom, origin, refresh); len(children) > 0 {
+			item.ChildMenu = fyne.NewMenu("", children...)
+		}
+	default:
+		return nil
+	}
+
+	node.BindString("icon", dom, func(value string) {
+		item.Icon = Icons.Icon(value)
+		refresh()
+	})
+
+	node.BindBool("disabled", dom, func(value bool) {
+		item.Disabled = value
+		refresh()
+	})
+
+	if keys := node.GetAttr("shortcut"); keys != "" {
+		if shortcut, err := ParseShortcut(keys); err == nil {
+			item.Shortcut = shortcut
+		} else {
+			log.Println("menu item shortcut error:", err)
+		}
+	}
+
+	if id := node.GetAttr("id"); id != "" {
+		dom.menuRefs[id] = item
+	}
+
+	return item
+}
+
+// parseContextMenu builds the popup menu of a <context-menu> node attached to
+// origin.
+func parseContextMenu(node *XMLNode, origin *XMLNode, dom *DOM) *fyne.Menu {
+	return fyne.NewMenu("", parseMenuItems(node, dom, origin, func() {})...)
+}
@@ -0,0 +1,129 @@
+package reago
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ModalButton is a button at the bottom of a modal.
+type ModalButton struct {
+	Label string
+	// Primary highlights the button as the main action.
+	Primary bool
+	// Action is called on tap. Without one, the button closes the modal.
+	Action func(modal *Modal)
+}
+
+type ModalOptions struct {
+	Title string
+	// DOM provides the state and callbacks of the template. The modal renders
+	// into a clone of it with a copy of its values, which are written back
+	// when the modal is resolved, and values it creates stay scoped to the
+	// modal. Defaults to the DOM shown in the window.
+	DOM           *DOM
+	Buttons       []ModalButton
+	Width, Height float32
+}
+
+// Modal is a dialog rendered from a template. Besides the callbacks of its
+// DOM, the template can call modal.close and modal.resolve(value), e.g.
+// bind:click="modal.resolve(name)" to return the "name" state value.
+type Modal struct {
+	*Dialog[any]
+	dom *DOM
+	dlg *dialog.CustomDialog
+}
+
+// Modal shows a template in a dialog. It resolves with the value given to
+// Resolve, or as cancelled when closed.
+func (window *Window) Modal(content string, opts ModalOptions, callback func(DialogResult[any])) *Modal {
+	parent := opts.DOM
+	if parent == nil {
+		parent = window.dom
+	}
+	if parent == nil {
+		parent = NewDOM()
+	}
+
+	modal := &Modal{}
+	modal.Dialog = newDialog(func(result DialogResult[any]) {
+		if !result.Cancelled && result.Err == nil && modal.dom != nil {
+			modal.dom.state.writeTo(parent.state)
+		}
+		if callback != nil {
+			callback(result)
+		}
+	})
+	if window.w == nil {
+		modal.fail(errFragment)
+		return modal
+	}
+
+	modal.dom = parent.Clone()
+	modal.dom.state = parent.state.copy()
+	modal.dom.UseHandler("modal.close", modal.Close)
+	modal.dom.UseHandler("modal.resolve", modal.Resolve)
+	// includes resolve relative to the file of the DOM
+	modal.dom.template(content, modal.dom.file)
+
+	modal.dlg = dialog.NewCustomWithoutButtons(opts.Title, modal.dom.GetRoot(), window.w)
+
+	buttons := opts.Buttons
+	if buttons == nil {
+		buttons = []ModalButton{{Label: "Close"}}
+	}
+
+	var objects []fyne.CanvasObject
+	for _, button := range buttons {
+		action := button.Action
+		obj := widget.NewButton(button.Label, func() {
+			if action != nil {
+				action(modal)
+			} else {
+				modal.Close()
+			}
+		})
+		if button.Primary {
+			obj.Importance = widget.HighImportance
+		}
+		objects = append(objects, obj)
+	}
+	modal.dlg.SetButtons(objects)
+
+	modal.dlg.SetOnClosed(func() {
+		modal.resolve(DialogResult[any]{Cancelled: true})
+		// the router is shared with the DOM of the window
+		modal.dom.removeRouterViews()
+	})
+	modal.hide = modal.dlg.Hide
+
+	if opts.Width > 0 && opts.Height > 0 {
+		modal.dlg.Resize(fyne.NewSize(opts.Width, opts.Height))
+	}
+	modal.dlg.Show()
+
+	return modal
+}
+
+// Resolve closes the modal with value as its result, and writes the values
+// edited in the modal back to the state of its DOM.
+func (modal *Modal) Resolve(value any) {
+	modal.resolve(DialogResult[any]{Value: value})
+	if modal.hide != nil {
+		modal.hide()
+	}
+}
+
+// Update renders a new template in the modal.
+func (modal *Modal) Update(content string) {
+	if modal.dom != nil {
+		modal.dom.template(content, modal.dom.file)
+	}
+}
+
+// GetDOM returns the DOM the modal renders into, e.g. to read the values
+// entered in it.
+func (modal *Modal) GetDOM() *DOM {
+	return modal.dom
+}
@@ -33,8 +33,8 @@ func (parser *iParser) RegisterComponent(name string, component func(*XMLNode, *
 }
 
 func (parser *iParser) ParseXML(content string, target *DOM) fyne.CanvasObject {
-	var xmlRoot XMLNode
-	if err := xml.Unmarshal([]byte(content), &xmlRoot); err != nil {
+	xmlRoot, err := parser.parseRoot(content, target.parseMode)
+	if err != nil {
 		return widget.NewLabelWithStyle(
 			"component_error: "+err.Error(),
 			fyne.TextAlignCenter,
@@ -42,7 +42,19 @@ func (parser *iParser) ParseXML(content string, target *DOM) fyne.CanvasObject {
 		)
 	}
 
-	return parser.ParseNode(&xmlRoot, target)
+	return parser.ParseNode(xmlRoot, target)
+}
+
+func (parser *iParser) parseRoot(content string, mode ParseMode) (*XMLNode, error) {
+	if mode == ParseLenient {
+		return parseLenient(content)
+	}
+
+	var xmlRoot XMLNode
+	if err := xml.Unmarshal([]byte(content), &xmlRoot); err != nil {
+		return nil, err
+	}
+	return &xmlRoot, nil
 }
 
 func (parser *iParser) ParseNode(node *XMLNode, target *DOM) fyne.CanvasObject {
@@ -50,6 +62,31 @@ func (parser *iParser) ParseNode(node *XMLNode, target *DOM) fyne.CanvasObject {
 		return nil
 	}
 
+	// <context-menu> children are not content, so they are hidden from the handler
+	var menuNode *XMLNode
+	for i := range node.Nodes {
+		if node.Nodes[i].GetTag() == "context-menu" {
+			menuNode = &node.Nodes[i]
+
+			trimmed := *node
+			trimmed.Nodes = append(append([]XMLNode{}, node.Nodes[:i]...), node.Nodes[i+1:]...)
+			if node.Children != nil {
+				trimmed.Children = nil
+				for _, child := range node.Children {
+					if child.GetTag() != "context-menu" {
+						trimmed.Children = append(trimmed.Children, child)
+					}
+				}
+			}
+			node = &trimmed
+			break
+		}
+	}
+
+	// elements are registered before their children, in template order
+	element := &Element{Node: node, scope: target.scope}
+	target.elements = append(target.elements, element)
+
 	var obj fyne.CanvasObject
 
 	tag := node.GetTag()
@@ -66,6 +103,10 @@ func (parser *iParser) ParseNode(node *XMLNode, target *DOM) fyne.CanvasObject {
 		target.refs[id] = obj
 	}
 
+	element.Object = obj
+	obj = wrapEvents(obj, node, menuNode, target)
+	element.Target = obj
+
 	node.BindBool("hidden", target, func(value bool) {
 		if value {
 			obj.Hide()
@@ -79,6 +120,17 @@ func (parser *iParser) ParseNode(node *XMLNode, target *DOM) fyne.CanvasObject {
 
 func (parser *iParser) ParseChildren(node *XMLNode, target *DOM) []fyne.CanvasObject {
 	var children []fyne.CanvasObject
+	if node.Children != nil {
+		for _, child := range node.Children {
+			if !child.IsText() {
+				children = append(children, Parser.ParseNode(&child, target))
+			} else if text := strings.TrimSpace(child.Content); text != "" {
+				children = append(children, Parser.ParseNode(textNode(text), target))
+			}
+		}
+		return children
+	}
+
 	for _, child := range node.Nodes {
 		children = append(children, Parser.ParseNode(&child, target))
 	}
@@ -0,0 +1,141 @@
+package reago
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ParseMode selects how the templates of a DOM are parsed.
+type ParseMode int
+
+const (
+	// ParseStrict parses templates as XML with a single root element.
+	ParseStrict ParseMode = iota
+	// ParseLenient accepts templates written like HTML: several roots,
+	// wrapped in a <stack>, void elements such as <br> or <img> without a
+	// closing slash, HTML entities such as &nbsp;, unclosed tags, and text
+	// between elements, kept in order in XMLNode.Children and rendered as
+	// labels by containers.
+	ParseLenient
+)
+
+// voidElements are the HTML void elements without a ReaGO tag of the same
+// name that has content, such as <col> or <link>.
+var voidElements = []string{
+	"area", "base", "br", "embed", "hr", "meta", "param", "source", "track", "wbr",
+}
+
+// menuVoidElements are the HTML void elements whose ReaGO tag can have a
+// <context-menu> child. Anything else closes them.
+var menuVoidElements = []string{"img", "input"}
+
+func parseLenient(content string) (*XMLNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+	decoder.AutoClose = voidElements
+	decoder.Entity = xml.HTMLEntity
+
+	top := &XMLNode{}
+	stack := []*XMLNode{top}
+
+	// closeTo pops the open tags down to depth
+	closeTo := func(depth int) {
+		for len(stack) > depth {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			stack[len(stack)-1].appendNode(*current)
+		}
+	}
+	// closeMenuVoid closes an open <img> or <input> that gets content other
+	// than a <context-menu>
+	closeMenuVoid := func() {
+		if len(stack) > 1 && slices.Contains(menuVoidElements, stack[len(stack)-1].GetTag()) {
+			closeTo(len(stack) - 1)
+		}
+	}
+
+	// end is where the last token ended
+	var end int64
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF" && strings.TrimSpace(content[end:]) == "" {
+			// tags left open are closed by the end of the template, unless
+			// it ends in the middle of one
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end = decoder.InputOffset()
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			if token.Name.Local != "context-menu" {
+				closeMenuVoid()
+			}
+			stack = append(stack, &XMLNode{XMLName: token.Name, Attrs: token.Copy().Attr})
+		case xml.EndElement:
+			// the tag may have been closed already by closeMenuVoid
+			for depth := len(stack) - 1; depth > 0; depth-- {
+				if stack[depth].XMLName.Local == token.Name.Local {
+					closeTo(depth)
+					break
+				}
+			}
+		case xml.CharData:
+			if strings.TrimSpace(string(token)) != "" {
+				closeMenuVoid()
+			}
+			stack[len(stack)-1].appendText(string(token))
+		}
+	}
+
+	// close the tags left open
+	closeTo(1)
+
+	if len(top.Nodes) == 1 && strings.TrimSpace(top.Content) == "" {
+		return &top.Nodes[0], nil
+	}
+	if len(top.Children) == 0 {
+		return nil, errors.New("template is empty")
+	}
+
+	top.XMLName = xml.Name{Local: "stack"}
+	return top, nil
+}
+
+func (node *XMLNode) appendNode(child XMLNode) {
+	node.Nodes = append(node.Nodes, child)
+	node.Children = append(node.Children, child)
+}
+
+func (node *XMLNode) appendText(text string) {
+	node.Content += text
+
+	if last := len(node.Children) - 1; last >= 0 && node.Children[last].IsText() {
+		node.Children[last].Content += text
+		return
+	}
+	node.Children = append(node.Children, XMLNode{Content: text})
+}
+
+// IsText reports whether the node is a text run of XMLNode.Children.
+func (node *XMLNode) IsText() bool {
+	return node.XMLName.Local == ""
+}
+
+// textNode is the tag rendering a text run between elements.
+func textNode(text string) *XMLNode {
+	node := &XMLNode{XMLName: xml.Name{Local: "label"}, Content: text}
+	if strings.Contains(text, "{{") {
+		node.Attrs = []xml.Attr{{Name: xml.Name{Space: "bind", Local: "content"}}}
+	}
+	return node
+}
@@ -0,0 +1,132 @@
+package reago
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLenientSingleRoot(t *testing.T) {
+	root, err := Parser.parseRoot(`<col><label>Hi</label></col>`, ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.GetTag() != "col" || len(root.Nodes) != 1 || root.Nodes[0].Content != "Hi" {
+		t.Errorf("parsed as %+v", root)
+	}
+}
+
+func TestParseLenientSeveralRoots(t *testing.T) {
+	root, err := Parser.parseRoot(`<label>a</label> text <label>b</label>`, ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.GetTag() != "stack" {
+		t.Fatalf("roots wrapped in <%s>, expected <stack>", root.GetTag())
+	}
+	if len(root.Nodes) != 2 || len(root.Children) != 3 {
+		t.Fatalf("%d nodes and %d children, expected 2 and 3", len(root.Nodes), len(root.Children))
+	}
+	if text := root.Children[1]; !text.IsText() || text.Content != " text " {
+		t.Errorf("text between the roots is %+v", text)
+	}
+}
+
+func TestParseLenientHTML(t *testing.T) {
+	root, err := Parser.parseRoot(`<col><label>a&nbsp;b</label><br><hr><label>c</label></col>`, ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tags []string
+	for _, node := range root.Nodes {
+		tags = append(tags, node.GetTag())
+	}
+	if len(tags) != 4 || tags[1] != "br" || tags[2] != "hr" || tags[3] != "label" {
+		t.Fatalf("void elements parsed as %v, expected siblings", tags)
+	}
+	if root.Nodes[0].Content != "a b" {
+		t.Errorf("entity parsed as %q", root.Nodes[0].Content)
+	}
+}
+
+func TestParseLenientUnclosedTags(t *testing.T) {
+	root, err := Parser.parseRoot(`<col><row><label>a</label>`, ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.GetTag() != "col" || len(root.Nodes) != 1 || root.Nodes[0].GetTag() != "row" || len(root.Nodes[0].Nodes) != 1 {
+		t.Errorf("unclosed tags parsed as %+v", root)
+	}
+}
+
+func TestParseLenientEmpty(t *testing.T) {
+	if _, err := Parser.parseRoot("", ParseLenient); err == nil {
+		t.Error("empty template parsed without error")
+	}
+}
+
+func TestParseStrictRejectsHTML(t *testing.T) {
+	if _, err := Parser.parseRoot(`<col><br></col>`, ParseStrict); err == nil {
+		t.Error("unclosed <br> parsed in strict mode")
+	}
+}
+
+func TestParseLenientCutInsideTag(t *testing.T) {
+	for _, content := range []string{
+		`<col><button text="ab`,
+		`<col><button`,
+		`<col><label>a</lab`,
+		`<col><!-- comment`,
+	} {
+		if _, err := Parser.parseRoot(content, ParseLenient); err == nil {
+			t.Errorf("%s parsed without error", content)
+		}
+	}
+}
+
+func TestParseLenientContextMenuInVoidElements(t *testing.T) {
+	root, err := Parser.parseRoot(`<col>
+		<img src="a.png">
+			<context-menu><item>Copy</item></context-menu>
+		<input>
+			<context-menu><item>Paste</item></context-menu>
+		<label>b</label>
+	</col>`, ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(root.Nodes) != 3 {
+		t.Fatalf("%d children in <col>, expected img, input and label", len(root.Nodes))
+	}
+	for i, tag := range []string{"img", "input"} {
+		node := root.Nodes[i]
+		if node.GetTag() != tag || len(node.Nodes) != 1 || node.Nodes[0].GetTag() != "context-menu" {
+			t.Errorf("<%s> parsed as %+v, expected it to keep its <context-menu>", tag, node)
+		}
+	}
+	if root.Nodes[2].GetTag() != "label" {
+		t.Errorf("last child is <%s>, expected <label>", root.Nodes[2].GetTag())
+	}
+}
+
+func TestParseLenientVoidElementsClosedByContent(t *testing.T) {
+	root, err := Parser.parseRoot(`<col><img src="a.png"><label>b</label><input>text</input><img src="c.png" /></col>`, ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tags []string
+	for _, node := range root.Nodes {
+		tags = append(tags, node.GetTag())
+	}
+	if strings.Join(tags, " ") != "img label input img" {
+		t.Fatalf("children parsed as %v, expected siblings", tags)
+	}
+	if len(root.Nodes[0].Nodes) != 0 || len(root.Nodes[2].Nodes) != 0 {
+		t.Error("void elements got children")
+	}
+	if !strings.Contains(root.Content, "text") {
+		t.Errorf("text after <input> parsed as %q in <col>", root.Content)
+	}
+}
@@ -1,6 +1,10 @@
 package reago
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
@@ -250,12 +254,13 @@ func init() {
 
 	/** <button> */
 	Parser.RegisterTag("button", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		obj := widget.NewButtonWithIcon("", nil, func() {})
+		button := newKeyButton(node, dom)
+		obj := &button.Button
 
 		obj.OnTapped = node.BindCallback("click", dom)
 
 		node.BindString("icon", dom, func(value string) {
-			obj.Icon = theme.Icon(fyne.ThemeIconName(value))
+			obj.Icon = Icons.Icon(value)
 			obj.Refresh()
 		})
 
@@ -271,29 +276,24 @@ func init() {
 			}
 		})
 
-		return obj
+		return button
 	})
 
 	/** <input> */
 	Parser.RegisterTag("input", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		var entry *widget.Entry
+		entry := newInputEntry(false, node.GetAttr("type") == "password")
 
 		switch node.GetAttr("type") {
-		case "password":
-			entry = widget.NewPasswordEntry()
 		case "number":
-			entry = widget.NewEntry()
 			entry.Validator = validation.NewRegexp(`^\d+$`, "Only numbers are allowed")
 		case "email":
-			entry = widget.NewEntry()
 			entry.Validator = validation.NewRegexp(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`, "Invalid email address")
 		case "url":
-			entry = widget.NewEntry()
 			entry.Validator = validation.NewRegexp(`^https?://.+$`, "Invalid URL")
-		default:
-			entry = widget.NewEntry()
 		}
 
+		entry.bindEvents(node, dom)
+
 		node.BindString("placeholder", dom, func(value string) {
 			entry.SetPlaceHolder(value)
 		})
@@ -325,7 +325,8 @@ func init() {
 
 	/** <textarea> */
 	Parser.RegisterTag("textarea", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		entry := widget.NewMultiLineEntry()
+		entry := newInputEntry(true, false)
+		entry.bindEvents(node, dom)
 
 		node.BindString("placeholder", dom, func(value string) {
 			entry.SetPlaceHolder(value)
@@ -358,7 +359,8 @@ func init() {
 
 	/** <checkbox> */
 	Parser.RegisterTag("checkbox", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		obj := widget.NewCheck(node.GetAttr("label"), func(bool) {})
+		check := newKeyCheck(node.GetAttr("label"), node, dom)
+		obj := &check.Check
 
 		node.BindString("label", dom, func(value string) {
 			obj.Text = value
@@ -377,29 +379,191 @@ func init() {
 			}
 		})
 
-		return obj
+		return check
 	})
 
 	/** <radio> */
 	Parser.RegisterTag("radio", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		// TODO: Implement radio group
+		obj := widget.NewRadioGroup([]string{}, func(string) {})
+
+		// options are displayed by label, but bound by value
+		var values []string
+		labelOf := func(value string) string {
+			for i, v := range values {
+				if v == value {
+					return obj.Options[i]
+				}
+			}
+			return ""
+		}
+		valueOf := func(label string) string {
+			for i, option := range obj.Options {
+				if option == label {
+					return values[i]
+				}
+			}
+			return ""
+		}
 
-		var children []string
-		for _, child := range node.Nodes {
-			if child.GetTag() == "option" {
-				children = append(children, child.GetAttr("value"))
+		if node.HasBind("options") {
+			labelField := node.GetAttr("label-field")
+			if labelField == "" {
+				labelField = "Label"
+			}
+			valueField := node.GetAttr("value-field")
+			if valueField == "" {
+				valueField = "Value"
 			}
+
+			node.BindList("options", dom, func(value []any) {
+				var options []string
+				values = nil
+				for _, v := range value {
+					if str, ok := v.(string); ok {
+						options = append(options, str)
+						values = append(values, str)
+						continue
+					}
+					fields := ParseStruct(v)
+					options = append(options, fields[labelField])
+					values = append(values, fields[valueField])
+				}
+				obj.Options = options
+				obj.Refresh()
+
+				if bind := node.GetBind("value"); bind != "" && dom.state.Has(bind) {
+					obj.SetSelected(labelOf(dom.state.GetString(bind).Get()))
+				}
+			})
+		} else {
+			var options []string
+			for _, child := range node.Nodes {
+				if child.GetTag() == "option" {
+					label := child.GetContent()
+					value := child.GetAttr("value")
+					if label == "" {
+						label = value
+					}
+					if !child.HasAttr("value") {
+						value = label
+					}
+					options = append(options, label)
+					values = append(values, value)
+				}
+			}
+			obj.Options = options
 		}
 
-		obj := widget.NewRadioGroup(children, func(string) {})
+		setValue := node.BindString("value", dom, func(value string) {
+			obj.SetSelected(labelOf(value))
+		})
+		obj.OnChanged = func(label string) {
+			if setValue != nil {
+				setValue(valueOf(label))
+			}
+		}
 
-		if node.GetAttrBool("readonly") || node.GetAttrBool("disabled") {
+		obj.Horizontal = node.GetAttr("dir") == "horizontal"
+		obj.Required = node.GetAttrBool("required")
+
+		if node.GetAttrBool("readonly") {
 			obj.Disable()
 		}
 
+		node.BindBool("disabled", dom, func(value bool) {
+			if value {
+				obj.Disable()
+			} else {
+				obj.Enable()
+			}
+		})
+
+		return obj
+	})
+
+	/** <shortcut> */
+	Parser.RegisterTag("shortcut", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
+		children := Parser.ParseChildren(node, dom)
+		obj := container.NewStack(children...)
+
+		shortcut, err := ParseShortcut(node.GetAttr("keys"))
+		if err != nil {
+			dom.reportError(fmt.Errorf("shortcut %s: %w", node.GetAttr("keys"), err))
+			return obj
+		}
+		if node.GetBind("trigger") == "" {
+			dom.reportError(errors.New("shortcut " + node.GetAttr("keys") + " has no bind:trigger"))
+			return obj
+		}
+
+		// with children, the shortcut only applies while focus is inside them
+		binding := &shortcutBinding{
+			kind:     shortcutTemplate,
+			shortcut: shortcut,
+			callback: node.GetBind("trigger"),
+			node:     node,
+			dom:      dom,
+		}
+		if len(children) > 0 {
+			binding.scope = obj
+		} else {
+			obj.Hide()
+		}
+
+		// conflicts are reported when the window registers the shortcuts
+		dom.shortcuts = append(dom.shortcuts, binding)
+
 		return obj
 	})
 
+	/** <router> */
+	Parser.RegisterTag("router", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
+		return mountRouter(node, dom)
+	})
+
+	/** <toasts> */
+	Parser.RegisterTag("toasts", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
+		host := newToastHost(node.GetAttr("position"), node.GetAttrInt("max"), dom)
+		dom.toasts = host
+		return host.obj
+	})
+
+	/** <include> */
+	Parser.RegisterTag("include", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
+		return mountInclude(node, dom)
+	})
+
+	/** <link> */
+	Parser.RegisterTag("link", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
+		link := newKeyHyperlink("", node, dom)
+		obj := &link.Hyperlink
+
+		node.BindContent(dom, func(value string) {
+			obj.SetText(value)
+		})
+
+		to := node.GetAttr("to")
+		node.BindString("to", dom, func(value string) {
+			to = value
+		})
+
+		obj.OnTapped = func() {
+			router := dom.UseRouter()
+			switch {
+			case node.GetAttr("action") == "back":
+				router.Back()
+			case node.GetAttr("action") == "forward":
+				router.Forward()
+			case node.GetAttrBool("replace"):
+				router.Replace(to)
+			default:
+				router.Navigate(to)
+			}
+		}
+
+		return link
+	})
+
 	/** <br> */
 	Parser.RegisterTag("br", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
 		return widget.NewLabel("")
@@ -444,7 +608,8 @@ func init() {
 
 	/** <a> */
 	Parser.RegisterTag("a", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		obj := widget.NewHyperlink(node.GetContent(), nil)
+		link := newKeyHyperlink(node.GetContent(), node, dom)
+		obj := &link.Hyperlink
 
 		node.BindContent(dom, func(value string) {
 			obj.SetText(value)
@@ -454,15 +619,24 @@ func init() {
 			obj.SetURLFromString(value)
 		})
 
-		return obj
+		return link
 	})
 
 	/** <icon> */
 	Parser.RegisterTag("icon", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		// TODO: this is probably wrong
-		name := node.GetContent()
-		icon := fyne.NewStaticResource(name, nil)
-		return widget.NewIcon(icon)
+		obj := widget.NewIcon(nil)
+
+		if node.HasAttr("src") || node.HasBind("src") {
+			node.BindString("src", dom, func(value string) {
+				obj.SetResource(Icons.Icon(value))
+			})
+		} else {
+			node.BindContent(dom, func(value string) {
+				obj.SetResource(Icons.Icon(value))
+			})
+		}
+
+		return obj
 	})
 
 	/** <progress> */
@@ -505,7 +679,8 @@ func init() {
 
 	/** <select> */
 	Parser.RegisterTag("select", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		obj := widget.NewSelect([]string{}, func(string) {})
+		sel := newKeySelect(node, dom)
+		obj := &sel.Select
 
 		if node.HasBind("options") {
 			node.BindList("options", dom, func(value []any) {
@@ -544,7 +719,7 @@ func init() {
 			}
 		})
 
-		return obj
+		return sel
 	})
 
 	/** <combobox> */
@@ -592,7 +767,8 @@ func init() {
 
 	/** <slider> */
 	Parser.RegisterTag("slider", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		obj := widget.NewSlider(0, 100)
+		slider := newKeySlider(0, 100, node, dom)
+		obj := &slider.Slider
 
 		node.BindFloat("value", dom, func(value float64) {
 			obj.Value = value
@@ -627,7 +803,7 @@ func init() {
 			}
 		})
 
-		return obj
+		return slider
 	})
 
 	/** <code> */
@@ -651,14 +827,22 @@ func init() {
 	Parser.RegisterTag("toolbar", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
 		var items = []widget.ToolbarItem{}
 
-		for _, child := range node.Nodes {
+		for i := range node.Nodes {
+			child := &node.Nodes[i]
+
 			var item widget.ToolbarItem
 
 			tag := child.GetTag()
 			switch tag {
 			case "action":
-				icon := child.GetAttr("icon")
-				item = widget.NewToolbarAction(fyne.NewStaticResource(icon, nil), func() {})
+				item = parseToolbarAction(child, dom)
+			case "item":
+				children := Parser.ParseChildren(child, dom)
+				obj := container.NewHBox(children...)
+				if id := child.GetAttr("id"); id != "" {
+					dom.refs[id] = obj
+				}
+				item = &toolbarItem{obj: obj}
 			case "spacer":
 				item = widget.NewToolbarSpacer()
 			case "separator":
@@ -680,26 +864,29 @@ func init() {
 		}
 
 		bind := node.GetBind("items")
+		newFragment := dom.fragments()
 
-		obj := widget.NewList(
+		var obj *widget.List
+		obj = widget.NewList(
 			func() int {
 				list := dom.UseState().GetList(bind)
 				return list.container.Length()
 			},
 			func() fyne.CanvasObject {
-				fragment := dom.Clone()
+				fragment := newFragment(obj)
 				children := Parser.ParseChildren(node, fragment)
 				return container.NewHBox(children...)
 			},
-			func(idx widget.ListItemID, obj fyne.CanvasObject) {
-				fragment := dom.Clone()
+			func(idx widget.ListItemID, row fyne.CanvasObject) {
+				fragment := newFragment(obj)
 
 				item := dom.UseState().GetList(bind).GetValue(idx)
+				fragment.item = item
 				for key, value := range ParseStruct(item) {
 					fragment.UseState().String(key, value)
 				}
 
-				wrapper := obj.(*fyne.Container)
+				wrapper := row.(*fyne.Container)
 				wrapper.Objects = Parser.ParseChildren(node, fragment)
 				wrapper.Refresh()
 			},
@@ -750,35 +937,171 @@ func init() {
 
 	/** <tree> */
 	Parser.RegisterTag("tree", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
-		// TODO: circle back to this
-		/*
-			data := map[string][]string{
-				Parser.Register("":           {"Fruits", "Vegetables"}, // Root nodes
-				Parser.Register("Fruits":     {"Apple", "Banana", "Cherry"},
-				Parser.Register("Vegetables": {"Carrot", "Broccoli", "Potato"},
-			}
-			tree := widget.NewTree(
-				func(id string) []string {
-					// Return child nodes of the given parent
-					return data[id]
-				})
-				func(id string) bool {
-					// Return true if the node has children (i.e., is expandable)
-					_, hasChildren := data[id]
-					return hasChildren
-				})
-				func(id string) fyne.CanvasObject {
-					// Template for each row
-					return widget.NewLabel("Node")
-				})
-				func(id string, obj fyne.CanvasObject) {
-					// Set the text dynamically
-					label := obj.(*widget.Label)
-					label.SetText(id)
-				})
-			)
-		*/
-		return nil
+		if !node.HasBind("nodes") {
+			return widget.NewLabel("<missing bind property in tree>")
+		}
+
+		bind := node.GetBind("nodes")
+		newFragment := dom.fragments()
+
+		var childIDs func(string) []string
+		var isBranch func(string) bool
+		var getValue func(string) any
+		var data *ReactiveTree[any]
+
+		if source, ok := dom.trees[bind]; ok {
+			childIDs = source.children
+			isBranch = source.isBranch
+			getValue = func(id string) any { return id }
+		} else {
+			data = dom.UseState().GetTree(bind)
+			childIDs = data.ChildIDs
+			isBranch = data.IsBranch
+			getValue = data.GetValue
+		}
+
+		// <branch> and <leaf> children allow different templates per node kind,
+		// otherwise all children are used as the template for every node.
+		var branchTpl, leafTpl *XMLNode
+		for i := range node.Nodes {
+			switch node.Nodes[i].GetTag() {
+			case "branch":
+				branchTpl = &node.Nodes[i]
+			case "leaf":
+				leafTpl = &node.Nodes[i]
+			}
+		}
+		template := func(branch bool) *XMLNode {
+			if branch && branchTpl != nil {
+				return branchTpl
+			}
+			if !branch && leafTpl != nil {
+				return leafTpl
+			}
+			if branchTpl != nil {
+				return branchTpl
+			}
+			if leafTpl != nil {
+				return leafTpl
+			}
+			return node
+		}
+
+		var obj *widget.Tree
+		obj = widget.NewTree(
+			childIDs,
+			isBranch,
+			func(branch bool) fyne.CanvasObject {
+				tpl := template(branch)
+				if len(tpl.Nodes) == 0 {
+					return container.NewHBox(widget.NewLabel(""))
+				}
+				fragment := newFragment(obj)
+				children := Parser.ParseChildren(tpl, fragment)
+				return container.NewHBox(children...)
+			},
+			func(id widget.TreeNodeID, branch bool, row fyne.CanvasObject) {
+				fragment := newFragment(obj)
+
+				value := getValue(id)
+				fragment.item = value
+				fields := ParseStruct(value)
+				for key, value := range fields {
+					fragment.UseState().String(key, value)
+				}
+				fragment.UseState().String("id", id)
+				if len(fields) == 0 {
+					fragment.UseState().String("value", fmt.Sprintf("%v", value))
+				}
+
+				wrapper := row.(*fyne.Container)
+
+				tpl := template(branch)
+				if len(tpl.Nodes) == 0 {
+					label := id
+					if len(fields) == 0 {
+						label = fragment.UseState().GetString("value").Get()
+					}
+					wrapper.Objects = []fyne.CanvasObject{widget.NewLabel(label)}
+				} else {
+					wrapper.Objects = Parser.ParseChildren(tpl, fragment)
+				}
+				wrapper.Refresh()
+			},
+		)
+
+		if root := node.GetAttr("root"); root != "" {
+			obj.Root = root
+		}
+
+		if data != nil {
+			data.OnChange(func(_ map[string][]string, _ map[string]any) {
+				obj.Refresh()
+			})
+		}
+
+		setSelected := node.BindString("selected", dom, func(value string) {
+			if value == "" {
+				obj.UnselectAll()
+			} else {
+				obj.Select(value)
+			}
+		})
+
+		onSelect := node.BindCallback("select", dom)
+		obj.OnSelected = func(id widget.TreeNodeID) {
+			if setSelected != nil {
+				setSelected(id)
+			}
+			if onSelect != nil {
+				onSelect()
+			}
+		}
+
+		opened := make(map[string]bool)
+		setExpanded := node.BindList("expanded", dom, func(value []any) {
+			expanded := make(map[string]bool, len(value))
+			for _, v := range value {
+				if id, ok := v.(string); ok {
+					expanded[id] = true
+					if !obj.IsBranchOpen(id) {
+						opened[id] = true
+						obj.OpenBranch(id)
+					}
+				}
+			}
+			for id := range opened {
+				if !expanded[id] {
+					delete(opened, id)
+					obj.CloseBranch(id)
+				}
+			}
+		})
+
+		obj.OnBranchOpened = func(id widget.TreeNodeID) {
+			if setExpanded != nil && !opened[id] {
+				opened[id] = true
+				expanded := dom.UseState().GetList(node.GetBind("expanded")).Get()
+				setExpanded(append(slices.Clone(expanded), id))
+			}
+			if node.HasBind("open") {
+				dom.dispatch(node.GetBind("open"), &Event{Type: "open", Node: node, Item: id})
+			}
+		}
+		obj.OnBranchClosed = func(id widget.TreeNodeID) {
+			if setExpanded != nil && opened[id] {
+				delete(opened, id)
+				var expanded []any
+				for _, v := range dom.UseState().GetList(node.GetBind("expanded")).Get() {
+					if v != id {
+						expanded = append(expanded, v)
+					}
+				}
+				setExpanded(expanded)
+			}
+		}
+
+		return obj
 	})
 
 	/*
@@ -836,11 +1159,24 @@ func init() {
 	Parser.RegisterTag("scroll", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
 		children := Parser.ParseChildren(node, dom)
 
+		var obj *container.Scroll
 		if node.GetAttr("dir") == "horizontal" {
-			return container.NewHScroll(container.NewHBox(children...))
+			obj = container.NewHScroll(container.NewHBox(children...))
 		} else {
-			return container.NewVScroll(container.NewHBox(children...))
+			obj = container.NewVScroll(container.NewHBox(children...))
 		}
+
+		// the scroll handles scroll events itself, so bind:scroll isn't wrapped
+		if bind := node.GetBind("scroll"); bind != "" {
+			previous := obj.Offset
+			obj.OnScrolled = func(offset fyne.Position) {
+				delta := fyne.NewDelta(offset.X-previous.X, offset.Y-previous.Y)
+				previous = offset
+				dom.dispatch(bind, &Event{Type: "scroll", Node: node, Scroll: delta, Offset: offset})
+			}
+		}
+
+		return obj
 	})
 
 	/** <spacer> */
@@ -905,20 +1241,48 @@ func init() {
 		return obj
 	})
 
+	/** <img> */
 	Parser.RegisterTag("img", func(node *XMLNode, dom *DOM) fyne.CanvasObject {
 		obj := canvas.NewImageFromResource(nil)
+		loader := newImageLoader(obj, dom)
 
-		node.BindString("src", dom, func(value string) {
-			obj.Resource = fyne.NewStaticResource(value, nil)
-			obj.Refresh()
-		})
+		if node.HasAttr("placeholder") {
+			loader.placeholder = Icons.Icon(node.GetAttr("placeholder"))
+		}
+		if node.HasAttr("error") {
+			loader.broken = Icons.Icon(node.GetAttr("error"))
+		}
+
+		// bind:src-bytes and bind:src-uri create their state value with the
+		// right type, so it can be set after the template is parsed. bind:src
+		// also accepts one that already exists.
+		bind := node.GetBind("src")
+		if node.HasBind("src-bytes") {
+			bind = node.GetBind("src-bytes")
+			dom.state.GetBytes(bind)
+		} else if node.HasBind("src-uri") {
+			bind = node.GetBind("src-uri")
+			dom.state.GetURI(bind)
+		}
+		switch dom.state.binds[bind].(type) {
+		case *Reactive[[]byte]:
+			reactive := dom.state.GetBytes(bind)
+			reactive.OnChange(loader.LoadBytes)
+			loader.LoadBytes(reactive.Get())
+		case *Reactive[fyne.URI]:
+			reactive := dom.state.GetURI(bind)
+			reactive.OnChange(loader.LoadURI)
+			loader.LoadURI(reactive.Get())
+		default:
+			node.BindString("src", dom, loader.LoadSrc)
+		}
 
 		node.BindFloat("width", dom, func(value float64) {
-			obj.Resize(fyne.NewSize(float32(value), obj.Size().Height))
+			obj.SetMinSize(fyne.NewSize(float32(value), obj.MinSize().Height))
 		})
 
 		node.BindFloat("height", dom, func(value float64) {
-			obj.Resize(fyne.NewSize(obj.Size().Width, float32(value)))
+			obj.SetMinSize(fyne.NewSize(obj.MinSize().Width, float32(value)))
 		})
 
 		node.BindString("fill", dom, func(value string) {
@@ -968,3 +1332,38 @@ func init() {
 		return obj
 	})
 }
+
+func parseToolbarAction(node *XMLNode, dom *DOM) *ToolbarAction {
+	obj := NewToolbarAction(nil, node.BindCallback("click", dom))
+	obj.Toggle = node.GetAttrBool("toggle")
+
+	node.BindString("icon", dom, func(value string) {
+		obj.SetIcon(Icons.Icon(value))
+	})
+
+	node.BindContent(dom, func(value string) {
+		obj.SetText(value)
+	})
+
+	node.BindString("tooltip", dom, func(value string) {
+		obj.Tooltip = value
+	})
+
+	obj.OnToggled = node.BindBool("active", dom, func(value bool) {
+		obj.SetActive(value)
+	})
+
+	node.BindBool("disabled", dom, func(value bool) {
+		if value {
+			obj.Disable()
+		} else {
+			obj.Enable()
+		}
+	})
+
+	if id := node.GetAttr("id"); id != "" {
+		dom.refs[id] = obj
+	}
+
+	return obj
+}
@@ -0,0 +1,135 @@
+package reago
+
+import (
+	"context"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+type ProgressOptions struct {
+	Title   string
+	Message string
+	// Value drives the bar, from 0 to 1, in addition to the reporter.
+	Value *Reactive[float64]
+	// Infinite shows an infinite bar until a value is reported.
+	Infinite bool
+	// Cancel is the label of a button cancelling the context of the work.
+	// Without it, the dialog can't be cancelled by the user.
+	Cancel string
+}
+
+// ProgressReporter is handed to the work of a progress dialog to report how
+// far it got. It can be used from any goroutine.
+type ProgressReporter struct {
+	lock     sync.Mutex
+	done     bool
+	bar      *widget.ProgressBar
+	infinite *widget.ProgressBarInfinite
+	stack    *fyne.Container
+	message  *widget.Label
+}
+
+// Set sets the progress, from 0 to 1.
+func (reporter *ProgressReporter) Set(value float64) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	if reporter.done {
+		return
+	}
+	if reporter.infinite != nil {
+		reporter.infinite.Stop()
+		reporter.infinite = nil
+		reporter.stack.Objects = []fyne.CanvasObject{reporter.bar}
+		reporter.stack.Refresh()
+	}
+	reporter.bar.SetValue(value)
+}
+
+func (reporter *ProgressReporter) SetMessage(message string) {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	if !reporter.done {
+		reporter.message.SetText(message)
+	}
+}
+
+func (reporter *ProgressReporter) finish() {
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+
+	reporter.done = true
+	if reporter.infinite != nil {
+		reporter.infinite.Stop()
+	}
+}
+
+// Progress runs work in a goroutine while showing its progress, and closes
+// once it returns. The result is cancelled if the context was cancelled
+// before the work finished, with the error the work returned.
+func (window *Window) Progress(ctx context.Context, opts ProgressOptions, work func(ctx context.Context, progress *ProgressReporter) error) *Dialog[struct{}] {
+	d := newDialog[struct{}](nil)
+	if window.w == nil {
+		return d.fail(errFragment)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	reporter := &ProgressReporter{
+		bar:     widget.NewProgressBar(),
+		message: widget.NewLabel(opts.Message),
+	}
+	reporter.stack = container.NewStack(reporter.bar)
+	if opts.Infinite {
+		reporter.infinite = widget.NewProgressBarInfinite()
+		reporter.stack.Objects = []fyne.CanvasObject{reporter.infinite}
+	}
+
+	var listener binding.DataListener
+	if opts.Value != nil {
+		if !opts.Infinite {
+			reporter.Set(opts.Value.Get())
+		}
+		// fyne reports the current value when the listener is added, which
+		// isn't a progress yet for an infinite bar
+		initial, skip := opts.Value.Get(), opts.Infinite
+		listener = opts.Value.onChange(func(value float64) {
+			if skip && value == initial {
+				return
+			}
+			skip = false
+			reporter.Set(value)
+		})
+	}
+
+	dlg := dialog.NewCustomWithoutButtons(opts.Title, container.NewVBox(reporter.message, reporter.stack), window.w)
+	if opts.Cancel != "" {
+		dlg.SetButtons([]fyne.CanvasObject{widget.NewButton(opts.Cancel, cancel)})
+	}
+
+	d.hide = func() {
+		cancel()
+		reporter.finish()
+		if listener != nil {
+			opts.Value.removeListener(listener)
+		}
+		dlg.Hide()
+	}
+	dlg.Show()
+
+	go func() {
+		err := work(ctx, reporter)
+		cancelled := ctx.Err() != nil
+
+		d.hide()
+		d.resolve(DialogResult[struct{}]{Cancelled: cancelled, Err: err})
+	}()
+
+	return d
+}
@@ -0,0 +1,59 @@
+package reago_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+// progressBars returns whether the dialog on top of the window shows an
+// infinite bar and a determinate one.
+func progressBars(h *reagotest.Harness) (infinite bool, determinate bool) {
+	for _, obj := range test.LaidOutObjects(h.Window.Canvas().Overlays().Top()) {
+		switch obj := obj.(type) {
+		case *widget.ProgressBarInfinite:
+			infinite = infinite || obj.Visible()
+		case *widget.ProgressBar:
+			determinate = determinate || obj.Visible()
+		}
+	}
+	return infinite, determinate
+}
+
+func TestProgressInfiniteUntilValueReported(t *testing.T) {
+	h := reagotest.New(t)
+	mountEmpty(h)
+
+	value := reago.NewState().Float("progress", 0)
+	finish := make(chan struct{})
+	defer close(finish)
+
+	h.Window.Progress(context.Background(), reago.ProgressOptions{Value: value, Infinite: true}, func(ctx context.Context, progress *reago.ProgressReporter) error {
+		<-finish
+		return nil
+	})
+
+	// give the initial notification of the value time to arrive
+	time.Sleep(50 * time.Millisecond)
+	if infinite, determinate := progressBars(h); !infinite || determinate {
+		t.Fatalf("infinite bar shown: %t, determinate bar shown: %t, expected only the infinite one", infinite, determinate)
+	}
+
+	value.Set(0.5)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if infinite, determinate := progressBars(h); !infinite && determinate {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the reported value didn't replace the infinite bar")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
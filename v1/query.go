@@ -0,0 +1,172 @@
+package reago
+
+import (
+	"errors"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// Element is a template node and the object it was parsed into.
+type Element struct {
+	Node *XMLNode
+	// Object is what the tag created, as returned by the DOM getters.
+	Object fyne.CanvasObject
+	// Target is Object wrapped for the events bound on the node, as placed in
+	// the rendered tree.
+	Target fyne.CanvasObject
+
+	scope *elementScope
+}
+
+// elementScope groups the elements of a subtree that renders again on its
+// own, such as a route or an include, so the previous ones can be dropped.
+type elementScope struct {
+	parent  *elementScope
+	removed bool
+}
+
+func (scope *elementScope) live() bool {
+	for ; scope != nil; scope = scope.parent {
+		if scope.removed {
+			return false
+		}
+	}
+	return true
+}
+
+// renderScope runs render with the elements it parses in a new scope,
+// replacing the scope of the previous render, stored in scope.
+func (dom *DOM) renderScope(scope **elementScope, render func()) {
+	if *scope != nil {
+		(*scope).removed = true
+	}
+	*scope = &elementScope{parent: dom.scope}
+
+	previous := dom.scope
+	dom.scope = *scope
+	render()
+	dom.scope = previous
+}
+
+// selector is a parsed compound selector, e.g. `button#save[bind:click]`.
+type selector struct {
+	tag      string
+	id       string
+	attrs    []selectorAttr
+	contains string
+}
+
+type selectorAttr struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+func parseSelector(str string) (*selector, error) {
+	sel := &selector{}
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return nil, errors.New("empty selector")
+	}
+
+	if content, ok := strings.CutSuffix(str, ")"); ok {
+		i := strings.LastIndex(content, ":contains(")
+		if i == -1 {
+			return nil, errors.New("invalid selector: " + str)
+		}
+		sel.contains = strings.Trim(content[i+len(":contains("):], `"'`)
+		str = str[:i]
+	}
+
+	for str != "" {
+		switch str[0] {
+		case '#':
+			end := strings.IndexAny(str[1:], "#[")
+			if end == -1 {
+				end = len(str) - 1
+			}
+			sel.id = str[1 : end+1]
+			str = str[end+1:]
+		case '[':
+			end := strings.Index(str, "]")
+			if end == -1 {
+				return nil, errors.New("invalid selector, missing ]: " + str)
+			}
+			attr := selectorAttr{name: str[1:end]}
+			if name, value, ok := strings.Cut(attr.name, "="); ok {
+				attr.name, attr.value, attr.hasValue = name, strings.Trim(value, `"'`), true
+			}
+			sel.attrs = append(sel.attrs, attr)
+			str = str[end+1:]
+		default:
+			end := strings.IndexAny(str, "#[")
+			if end == -1 {
+				end = len(str)
+			}
+			sel.tag = strings.ToLower(str[:end])
+			str = str[end:]
+		}
+	}
+
+	return sel, nil
+}
+
+func (sel *selector) matches(node *XMLNode) bool {
+	if sel.tag != "" && sel.tag != "*" && node.GetTag() != sel.tag {
+		return false
+	}
+	if sel.id != "" && node.GetAttr("id") != sel.id {
+		return false
+	}
+	for _, attr := range sel.attrs {
+		var has bool
+		var value string
+		if name, ok := strings.CutPrefix(attr.name, "bind:"); ok {
+			has, value = node.HasBind(name), node.GetBind(name)
+		} else {
+			has, value = node.HasAttr(attr.name), node.GetAttr(attr.name)
+		}
+		if !has || (attr.hasValue && value != attr.value) {
+			return false
+		}
+	}
+	if sel.contains != "" && !strings.Contains(node.GetContent(), sel.contains) {
+		return false
+	}
+	return true
+}
+
+// QueryAll returns the elements of the current template matching selector,
+// in template order. A selector combines a tag, an #id, [attr] or
+// [attr=value] (including bind: attributes) and :contains(text), e.g.
+// `button[bind:click=save]` or `label:contains(Total)`.
+func (dom *DOM) QueryAll(selector string) ([]*Element, error) {
+	sel, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []*Element
+	live := dom.elements[:0]
+	for _, element := range dom.elements {
+		if !element.scope.live() {
+			continue
+		}
+		live = append(live, element)
+		if sel.matches(element.Node) {
+			elements = append(elements, element)
+		}
+	}
+	dom.elements = live
+	return elements, nil
+}
+
+// Query returns the first element matching selector, or nil.
+func (dom *DOM) Query(selector string) (*Element, error) {
+	elements, err := dom.QueryAll(selector)
+	if err != nil || len(elements) == 0 {
+		return nil, err
+	}
+	return elements[0], nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"slices"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/data/binding"
@@ -127,11 +128,25 @@ func (r *Reactive[T]) Get() T {
 }
 
 func (r *Reactive[T]) OnChange(callback func(T)) {
+	r.onChange(callback)
+}
+
+// onChange is OnChange returning the listener, so it can be removed with
+// removeListener.
+func (r *Reactive[T]) onChange(callback func(T)) binding.DataListener {
 	listener := binding.NewDataListener(func() {
 		callback(r.Get())
 	})
 	r.listeners = append(r.listeners, listener)
 	r.container.AddListener(listener)
+	return listener
+}
+
+func (r *Reactive[T]) removeListener(listener binding.DataListener) {
+	r.listeners = slices.DeleteFunc(r.listeners, func(l binding.DataListener) bool {
+		return l == listener
+	})
+	r.container.RemoveListener(listener)
 }
 
 func (r *Reactive[T]) ClearListeners() {
@@ -233,3 +248,111 @@ func (r *ReactiveList[T]) ClearListeners() {
 	}
 	r.listeners = nil
 }
+
+type ReactiveTree[T any] struct {
+	IReactive
+	container binding.UntypedTree
+	listeners []binding.DataListener
+}
+
+func NewReactiveTree[T any](container binding.UntypedTree) *ReactiveTree[T] {
+	return &ReactiveTree[T]{container: container}
+}
+
+func (rt *ReactiveTree[T]) Append(parent string, id string, value T) {
+	if err := rt.container.Append(parent, id, value); err != nil {
+		log.Println("ReactiveTree Append error:", err)
+	}
+}
+
+func (rt *ReactiveTree[T]) Prepend(parent string, id string, value T) {
+	if err := rt.container.Prepend(parent, id, value); err != nil {
+		log.Println("ReactiveTree Prepend error:", err)
+	}
+}
+
+func (rt *ReactiveTree[T]) Remove(id string) {
+	if err := rt.container.Remove(id); err != nil {
+		log.Println("ReactiveTree Remove error:", err)
+	}
+}
+
+func (rt *ReactiveTree[T]) Get() (map[string][]string, map[string]T) {
+	ids, items, err := rt.container.Get()
+	if err != nil {
+		log.Println("ReactiveTree Get error:", err)
+		return map[string][]string{}, map[string]T{}
+	}
+	result := make(map[string]T, len(items))
+	for id, item := range items {
+		typed, ok := item.(T)
+		if !ok {
+			log.Println("ReactiveTree Get type assertion failed for item:", item)
+			continue
+		}
+		result[id] = typed
+	}
+	return ids, result
+}
+
+func (rt *ReactiveTree[T]) GetValue(id string) T {
+	item, err := rt.container.GetValue(id)
+	if err != nil {
+		log.Println("ReactiveTree GetValue error:", err)
+		var zero T
+		return zero
+	}
+	typed, ok := item.(T)
+	if !ok {
+		log.Println("ReactiveTree GetValue type assertion failed for item:", item)
+		var zero T
+		return zero
+	}
+	return typed
+}
+
+func (rt *ReactiveTree[T]) Set(ids map[string][]string, values map[string]T) {
+	anyValues := make(map[string]any, len(values))
+	for id, v := range values {
+		anyValues[id] = v
+	}
+	if err := rt.container.Set(ids, anyValues); err != nil {
+		log.Println("ReactiveTree Set error:", err)
+	}
+}
+
+func (rt *ReactiveTree[T]) SetValue(id string, value T) {
+	if err := rt.container.SetValue(id, value); err != nil {
+		log.Println("ReactiveTree SetValue error:", err)
+	}
+}
+
+func (rt *ReactiveTree[T]) ChildIDs(id string) []string {
+	return rt.container.ChildIDs(id)
+}
+
+// IsBranch reports whether id is a branch, i.e. a key of the ids of the
+// tree, even without children: an empty folder is still a branch.
+func (rt *ReactiveTree[T]) IsBranch(id string) bool {
+	ids, _, err := rt.container.Get()
+	if err != nil {
+		return false
+	}
+	_, ok := ids[id]
+	return ok
+}
+
+func (rt *ReactiveTree[T]) OnChange(callback func(map[string][]string, map[string]T)) {
+	listener := binding.NewDataListener(func() {
+		callback(rt.Get())
+	})
+	rt.listeners = append(rt.listeners, listener)
+	rt.container.AddListener(listener)
+}
+
+func (rt *ReactiveTree[T]) ClearListeners() {
+	for _, listener := range rt.listeners {
+		rt.container.RemoveListener(listener)
+	}
+	rt.listeners = nil
+}
@@ -0,0 +1,72 @@
+package reagotest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	reago "github.com/victormga/reago/v1"
+)
+
+// failRecorder records the failures of an assertion instead of failing the
+// test.
+type failRecorder struct {
+	testing.TB
+	failed bool
+}
+
+func (recorder *failRecorder) Errorf(format string, args ...any) {
+	recorder.failed = true
+}
+
+func TestAssertFailures(t *testing.T) {
+	h := New(t)
+
+	dom := reago.NewDOM()
+	dom.UseState().String("user", "ana")
+	dom.Template(`<label id="status">Ready</label>`)
+	h.Mount(dom)
+
+	parent := h.t
+	for name, assert := range map[string]func(){
+		"text":          func() { h.AssertText("#status", "Done") },
+		"state":         func() { h.AssertState("user", "bob") },
+		"missing state": func() { h.AssertState("other", "") },
+		"hidden":        func() { h.AssertHidden("#status") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			recorder := &failRecorder{TB: t}
+			h.t = recorder
+			defer func() { h.t = parent }()
+
+			assert()
+			if !recorder.failed {
+				t.Errorf("the assertion passed, expected a failure")
+			}
+		})
+	}
+}
+
+func TestAssertSnapshotMissingGolden(t *testing.T) {
+	h := New(t)
+	t.Chdir(t.TempDir())
+
+	dom := reago.NewDOM()
+	dom.Template(`<label>Ready</label>`)
+	h.Mount(dom)
+
+	recorder := &failRecorder{TB: t}
+	h.t = recorder
+	h.AssertSnapshot("ready")
+	h.t = t
+
+	if !recorder.failed {
+		t.Error("the snapshot passed without golden files, expected a failure")
+	}
+	if _, err := os.Stat(filepath.Join("testdata", "ready.png")); !os.IsNotExist(err) {
+		t.Error("the golden image was written without -reagotest.update")
+	}
+	if _, err := os.Stat(filepath.Join("testdata", "failed", "ready.png")); err != nil {
+		t.Errorf("what was rendered wasn't written: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package reagotest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	reago "github.com/victormga/reago/v1"
+)
+
+// FakeClock is a reago.Clock whose time only moves with Advance.
+type FakeClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	f     func()
+}
+
+func NewFakeClock() *FakeClock {
+	return &FakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return clock.now
+}
+
+func (clock *FakeClock) AfterFunc(d time.Duration, f func()) reago.Timer {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	timer := &fakeTimer{clock: clock, at: clock.now.Add(d), f: f}
+	clock.timers = append(clock.timers, timer)
+	return timer
+}
+
+// Advance moves the time forward, running the timers due in order. Timers
+// they start are run too if they are due.
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.lock.Lock()
+	end := clock.now.Add(d)
+	clock.lock.Unlock()
+
+	for {
+		clock.lock.Lock()
+		sort.SliceStable(clock.timers, func(i, j int) bool {
+			return clock.timers[i].at.Before(clock.timers[j].at)
+		})
+
+		if len(clock.timers) == 0 || clock.timers[0].at.After(end) {
+			clock.now = end
+			clock.lock.Unlock()
+			return
+		}
+
+		timer := clock.timers[0]
+		clock.timers = clock.timers[1:]
+		clock.now = timer.at
+		clock.lock.Unlock()
+
+		timer.f()
+	}
+}
+
+func (timer *fakeTimer) Stop() bool {
+	clock := timer.clock
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	for i, other := range clock.timers {
+		if other == timer {
+			clock.timers = append(clock.timers[:i:i], clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
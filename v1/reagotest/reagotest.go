@@ -0,0 +1,385 @@
+// Package reagotest mounts ReaGO DOMs on fyne's headless test driver, so
+// their UI can be tested with plain go test:
+//
+//	func TestLogin(t *testing.T) {
+//		h := reagotest.New(t)
+//
+//		dom := reago.NewDOM()
+//		dom.UseState().String("user", "")
+//		dom.Template(loginTemplate)
+//		h.Mount(dom)
+//
+//		h.Type("input#user", "ana")
+//		h.Click("button:contains(Login)")
+//		h.AssertState("user", "ana")
+//		h.AssertText("#status", "Welcome, ana")
+//	}
+package reagotest
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	reago "github.com/victormga/reago/v1"
+)
+
+// Harness is a DOM mounted in a headless window.
+type Harness struct {
+	t      testing.TB
+	App    *reago.App
+	Window *reago.Window
+	DOM    *reago.DOM
+	Clock  *FakeClock
+}
+
+// New creates a headless app with a 800x600 window. Create DOMs after it, as
+// rendering templates needs a window. The test app, and the fake clock
+// replacing the one of ReaGO, are reset when the test ends.
+func New(t testing.TB) *Harness {
+	t.Helper()
+
+	app := reago.NewAppFrom(test.NewTempApp(t))
+
+	clock := NewFakeClock()
+	reago.SetClock(clock)
+	t.Cleanup(func() {
+		reago.SetClock(nil)
+	})
+
+	window := app.NewWindow("reagotest", "reagotest", 800, 600)
+
+	return &Harness{t: t, App: app, Window: window, Clock: clock}
+}
+
+// Mount shows dom in the window.
+func (h *Harness) Mount(dom *reago.DOM) {
+	h.DOM = dom
+	h.Window.Show(dom)
+}
+
+// Resize resizes the window, e.g. to test a responsive layout.
+func (h *Harness) Resize(width float32, height float32) {
+	h.Window.Resize(width, height)
+}
+
+// FindAll returns the objects of the elements matching selector (see
+// reago.DOM.QueryAll).
+func (h *Harness) FindAll(selector string) []fyne.CanvasObject {
+	h.t.Helper()
+
+	elements, err := h.DOM.QueryAll(selector)
+	if err != nil {
+		h.t.Fatalf("reagotest: %v", err)
+	}
+
+	var objects []fyne.CanvasObject
+	for _, element := range elements {
+		objects = append(objects, element.Object)
+	}
+	return objects
+}
+
+// Find returns the object of the first element matching selector, failing
+// the test if there is none.
+func (h *Harness) Find(selector string) fyne.CanvasObject {
+	h.t.Helper()
+	return h.element(selector).Object
+}
+
+func (h *Harness) element(selector string) *reago.Element {
+	h.t.Helper()
+
+	element, err := h.DOM.Query(selector)
+	if err != nil {
+		h.t.Fatalf("reagotest: %v", err)
+	}
+	if element == nil {
+		h.t.Fatalf("reagotest: no element matches %q", selector)
+	}
+	return element
+}
+
+// find returns the outermost object implementing T in the rendered tree of
+// the element, which is what receives the event in a real window.
+func find[T any](h *Harness, selector string, what string) T {
+	h.t.Helper()
+
+	element := h.element(selector)
+	for _, obj := range walk(element.Target) {
+		if found, ok := obj.(T); ok {
+			return found
+		}
+	}
+
+	h.t.Fatalf("reagotest: %q is not %s", selector, what)
+	var zero T
+	return zero
+}
+
+// walk lists obj and everything it renders, outermost first.
+func walk(obj fyne.CanvasObject) []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{obj}
+	for i := 0; i < len(objects); i++ {
+		switch current := objects[i].(type) {
+		case *fyne.Container:
+			objects = append(objects, current.Objects...)
+		case fyne.Widget:
+			objects = append(objects, test.WidgetRenderer(current).Objects()...)
+		}
+	}
+	return objects
+}
+
+// pointAt is a pointer event at the center of the first text obj renders, or
+// else of obj, as e.g. hyperlinks only react to taps over their text.
+func (h *Harness) pointAt(obj any) *fyne.PointEvent {
+	event := &fyne.PointEvent{}
+	object, ok := obj.(fyne.CanvasObject)
+	if !ok {
+		return event
+	}
+
+	driver := fyne.CurrentApp().Driver()
+	origin := driver.AbsolutePositionForObject(object)
+
+	target := object
+	for _, child := range walk(object) {
+		if text, ok := child.(*canvas.Text); ok && text.Visible() && text.Text != "" {
+			target = text
+			break
+		}
+	}
+
+	center := driver.AbsolutePositionForObject(target).Add(fyne.NewPos(target.Size().Width/2, target.Size().Height/2))
+	event.AbsolutePosition = center
+	event.Position = center.Subtract(origin)
+	return event
+}
+
+// Click taps the element at its center, or over its text.
+func (h *Harness) Click(selector string) {
+	h.t.Helper()
+
+	tappable := find[fyne.Tappable](h, selector, "clickable")
+	tappable.Tapped(h.pointAt(tappable))
+}
+
+func (h *Harness) DoubleClick(selector string) {
+	h.t.Helper()
+
+	tappable := find[fyne.DoubleTappable](h, selector, "double clickable")
+	tappable.DoubleTapped(h.pointAt(tappable))
+}
+
+func (h *Harness) RightClick(selector string) {
+	h.t.Helper()
+
+	tappable := find[fyne.SecondaryTappable](h, selector, "right clickable")
+	tappable.TappedSecondary(h.pointAt(tappable))
+}
+
+// Focus focuses the element, as a tap on it would.
+func (h *Harness) Focus(selector string) {
+	h.t.Helper()
+	h.Window.Canvas().Focus(find[fyne.Focusable](h, selector, "focusable"))
+}
+
+// Type focuses the element and types text into it.
+func (h *Harness) Type(selector string, text string) {
+	h.t.Helper()
+
+	focusable := find[fyne.Focusable](h, selector, "focusable")
+	h.Window.Canvas().Focus(focusable)
+	test.Type(focusable, text)
+}
+
+// Select picks option in a <select>, <combobox>, <radio> or <tabs> (by
+// title), checks a <checkbox> with "true", or selects an item of a <tree> by
+// id or of a <list> by index.
+func (h *Harness) Select(selector string, option string) {
+	h.t.Helper()
+
+	switch obj := embeddedWidget(h.Find(selector)).(type) {
+	case *widget.Select:
+		obj.SetSelected(option)
+	case *widget.SelectEntry:
+		obj.SetText(option)
+	case *widget.RadioGroup:
+		obj.SetSelected(option)
+	case *widget.Check:
+		obj.SetChecked(option == "true")
+	case *widget.Tree:
+		obj.Select(option)
+	case *widget.List:
+		index, err := strconv.Atoi(option)
+		if err != nil {
+			h.t.Fatalf("reagotest: list option must be an index, got %q", option)
+		}
+		obj.Select(index)
+	case *container.AppTabs:
+		for i, item := range obj.Items {
+			if item.Text == option {
+				obj.SelectIndex(i)
+				return
+			}
+		}
+		h.t.Fatalf("reagotest: %q has no tab %q", selector, option)
+	default:
+		h.t.Fatalf("reagotest: can't select in %q (%T)", selector, obj)
+	}
+}
+
+// Shortcut types a key combination, such as "Ctrl+S", in the window.
+func (h *Harness) Shortcut(keys string) {
+	h.t.Helper()
+
+	if err := h.Window.TypeShortcut(keys); err != nil {
+		h.t.Fatalf("reagotest: %v", err)
+	}
+}
+
+// Advance moves the clock of ReaGO forward, running the timers due, e.g. the
+// dismissal of toasts.
+func (h *Harness) Advance(d time.Duration) {
+	h.Clock.Advance(d)
+}
+
+// Text returns the text shown by the element: the value of a widget holding
+// one, or else all the text it renders, separated by spaces.
+func (h *Harness) Text(selector string) string {
+	h.t.Helper()
+
+	element := h.element(selector)
+	if text, ok := widgetText(element.Object); ok {
+		return text
+	}
+
+	var texts []string
+	for _, obj := range walk(element.Target) {
+		if text, ok := obj.(*canvas.Text); ok && text.Visible() && text.Text != "" {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+func widgetText(obj fyne.CanvasObject) (string, bool) {
+	switch obj := embeddedWidget(obj).(type) {
+	case *widget.Label:
+		return obj.Text, true
+	case *widget.Button:
+		return obj.Text, true
+	case *widget.Entry:
+		return obj.Text, true
+	case *widget.SelectEntry:
+		return obj.Text, true
+	case *widget.Hyperlink:
+		return obj.Text, true
+	case *widget.Check:
+		return obj.Text, true
+	case *widget.Select:
+		return obj.Selected, true
+	case *widget.RadioGroup:
+		return obj.Selected, true
+	case *widget.RichText:
+		return obj.String(), true
+	case *canvas.Text:
+		return obj.Text, true
+	}
+	return "", false
+}
+
+// embeddedWidget returns the fyne widget embedded first by the widgets of
+// ReaGO, such as the entry of <input>, or else obj.
+func embeddedWidget(obj fyne.CanvasObject) fyne.CanvasObject {
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return obj
+	}
+	if value.Elem().Type().PkgPath() != reflect.TypeOf(reago.Element{}).PkgPath() || value.Elem().NumField() == 0 {
+		return obj
+	}
+
+	field := value.Elem().Field(0)
+	if !value.Elem().Type().Field(0).Anonymous || field.Type().PkgPath() != reflect.TypeOf(widget.Label{}).PkgPath() {
+		return obj
+	}
+	if embedded, ok := field.Addr().Interface().(fyne.CanvasObject); ok {
+		return embedded
+	}
+	return obj
+}
+
+// settleTimeout is how long assertions wait for the UI to match, as fyne
+// delivers binding updates asynchronously.
+const settleTimeout = time.Second
+
+// eventually runs check until it passes or settleTimeout elapses.
+func eventually(check func() bool) bool {
+	deadline := time.Now().Add(settleTimeout)
+	for !check() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+// AssertText checks the text of the element (see Text), waiting for pending
+// binding updates.
+func (h *Harness) AssertText(selector string, expected string) {
+	h.t.Helper()
+
+	var text string
+	if !eventually(func() bool {
+		text = h.Text(selector)
+		return text == expected
+	}) {
+		h.t.Errorf("reagotest: text of %q is %q, expected %q", selector, text, expected)
+	}
+}
+
+// AssertState checks a state value, compared with reflect.DeepEqual, waiting
+// for pending binding updates.
+func (h *Harness) AssertState(name string, expected any) {
+	h.t.Helper()
+
+	var value any
+	var ok bool
+	if !eventually(func() bool {
+		value, ok = h.DOM.UseState().Value(name)
+		return ok && reflect.DeepEqual(value, expected)
+	}) {
+		if !ok {
+			h.t.Errorf("reagotest: state has no value %q", name)
+		} else {
+			h.t.Errorf("reagotest: state %q is %#v, expected %#v", name, value, expected)
+		}
+	}
+}
+
+func (h *Harness) AssertVisible(selector string) {
+	h.t.Helper()
+
+	if !eventually(func() bool { return h.element(selector).Target.Visible() }) {
+		h.t.Errorf("reagotest: %q is hidden, expected visible", selector)
+	}
+}
+
+func (h *Harness) AssertHidden(selector string) {
+	h.t.Helper()
+
+	if !eventually(func() bool { return !h.element(selector).Target.Visible() }) {
+		h.t.Errorf("reagotest: %q is visible, expected hidden", selector)
+	}
+}
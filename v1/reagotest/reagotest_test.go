@@ -0,0 +1,101 @@
+package reagotest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+func TestClick(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	count := dom.UseState().Int("count", 0)
+	dom.UseCallback("increment", func(node *reago.XMLNode) {
+		count.Set(count.Get() + 1)
+	})
+	dom.Template(`<button bind:click="increment">Add</button>`)
+	h.Mount(dom)
+
+	h.Click("button:contains(Add)")
+	h.Click("button:contains(Add)")
+	h.AssertState("count", 2)
+}
+
+func TestType(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.UseState().String("user", "")
+	dom.Template(`
+		<col>
+			<input id="user" bind:value="user" />
+			<label id="greeting" bind:content="">Hello {{user}}</label>
+		</col>
+	`)
+	h.Mount(dom)
+
+	h.Type("input#user", "ana")
+	h.AssertState("user", "ana")
+	h.AssertText("#user", "ana")
+	h.AssertText("#greeting", "Hello ana")
+}
+
+func TestShortcut(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	saved := dom.UseState().Bool("saved", false)
+	dom.UseCallback("save", func(node *reago.XMLNode) {
+		saved.Set(true)
+	})
+	dom.Template(`
+		<col>
+			<shortcut keys="Ctrl+S" bind:trigger="save" />
+			<label>Editor</label>
+		</col>
+	`)
+	h.Mount(dom)
+
+	h.Shortcut("Ctrl+S")
+	h.AssertState("saved", true)
+}
+
+func TestAdvance(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.Template(`<toasts id="toasts" />`)
+	h.Mount(dom)
+
+	h.Window.Toast("Saved", reago.ToastOptions{Duration: time.Second})
+	if text := h.Text("#toasts"); !strings.Contains(text, "Saved") {
+		t.Fatalf("toasts show %q, expected the toast", text)
+	}
+
+	h.Advance(500 * time.Millisecond)
+	if text := h.Text("#toasts"); !strings.Contains(text, "Saved") {
+		t.Fatalf("toasts show %q before the toast is due, expected the toast", text)
+	}
+
+	h.Advance(time.Second)
+	h.AssertText("#toasts", "")
+}
+
+func TestAssertVisibility(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	hidden := dom.UseState().Bool("hidden", false)
+	dom.Template(`<label id="status" bind:hidden="hidden">Ready</label>`)
+	h.Mount(dom)
+
+	h.AssertVisible("#status")
+	hidden.Set(true)
+	h.AssertHidden("#status")
+	hidden.Set(false)
+	h.AssertVisible("#status")
+}
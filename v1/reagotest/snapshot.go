@@ -0,0 +1,305 @@
+package reagotest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+
+	reago "github.com/victormga/reago/v1"
+)
+
+// update rewrites the golden files with what is rendered, e.g. with
+// go test -tags ci ./... -args -reagotest.update
+var update = flag.Bool("reagotest.update", false, "rewrite the golden snapshot files of reagotest")
+
+// SnapshotSize is the size DOMs are rendered at by AssertSnapshot.
+var SnapshotSize = fyne.NewSize(800, 600)
+
+// AssertSnapshot renders dom at SnapshotSize with fyne's test theme, and
+// compares the image and an XML dump of the widget tree to the golden files
+// testdata/<name>.png and testdata/<name>.xml. On a mismatch, what was
+// rendered, and an image highlighting the different pixels, are written to
+// testdata/failed. Missing golden files fail the test too, unless the tests
+// are run with -reagotest.update.
+//
+// Like templates, it needs an app with a window, e.g. from New.
+func AssertSnapshot(t testing.TB, dom *reago.DOM, name string) {
+	t.Helper()
+
+	window := test.NewTempWindow(t, dom.GetRoot())
+	window.Resize(SnapshotSize)
+
+	assertSnapshot(t, window.Canvas(), name)
+}
+
+// AssertSnapshot compares the window, toasts included, to golden files (see
+// AssertSnapshot).
+func (h *Harness) AssertSnapshot(name string) {
+	h.t.Helper()
+	assertSnapshot(h.t, h.Window.Canvas(), name)
+}
+
+func assertSnapshot(t testing.TB, c fyne.Canvas, name string) {
+	t.Helper()
+
+	settings := fyne.CurrentApp().Settings()
+	if current := settings.Theme(); current != test.Theme() {
+		settings.SetTheme(test.Theme())
+		t.Cleanup(func() { settings.SetTheme(current) })
+	}
+
+	imagePath := filepath.Join("testdata", name+".png")
+	treePath := filepath.Join("testdata", name+".xml")
+
+	goldenTree, treeErr := os.ReadFile(treePath)
+	goldenImage, imageErr := readPNG(imagePath)
+	if *update {
+		tree, img := settle(c)
+		if err := writeSnapshot(imagePath, treePath, tree, img); err != nil {
+			t.Fatalf("reagotest: %v", err)
+		}
+		return
+	}
+	if os.IsNotExist(treeErr) || os.IsNotExist(imageErr) {
+		tree, img := settle(c)
+		failedImage := filepath.Join("testdata", "failed", name+".png")
+		failedTree := filepath.Join("testdata", "failed", name+".xml")
+		if err := writeSnapshot(failedImage, failedTree, tree, img); err != nil {
+			t.Fatalf("reagotest: %v", err)
+		}
+		t.Errorf("reagotest: snapshot %s has no golden files, run the tests with -args -reagotest.update to create them\n(got %s)",
+			name, failedImage)
+		return
+	}
+	if treeErr != nil {
+		t.Fatalf("reagotest: %v", treeErr)
+	}
+	if imageErr != nil {
+		t.Fatalf("reagotest: %v", imageErr)
+	}
+
+	// bindings update asynchronously, so the UI is given time to match
+	var tree string
+	var img image.Image
+	matches := eventually(func() bool {
+		tree = dumpTree(c)
+		if tree != string(goldenTree) {
+			return false
+		}
+		img = c.Capture()
+		return diffImages(goldenImage, img) == nil
+	})
+	if matches {
+		return
+	}
+	if img == nil {
+		img = c.Capture()
+	}
+
+	failedImage := filepath.Join("testdata", "failed", name+".png")
+	failedTree := filepath.Join("testdata", "failed", name+".xml")
+	if err := writeSnapshot(failedImage, failedTree, tree, img); err != nil {
+		t.Fatalf("reagotest: %v", err)
+	}
+
+	if tree != string(goldenTree) {
+		t.Errorf("reagotest: snapshot %s: widget tree differs from %s, %s\n(got %s)",
+			name, treePath, firstDifference(string(goldenTree), tree), failedTree)
+	}
+	if diff := diffImages(goldenImage, img); diff != nil {
+		diffPath := filepath.Join("testdata", "failed", name+".diff.png")
+		if err := writePNG(diffPath, diff); err != nil {
+			t.Fatalf("reagotest: %v", err)
+		}
+		t.Errorf("reagotest: snapshot %s: image differs from %s\n(got %s, diff %s)",
+			name, imagePath, failedImage, diffPath)
+	}
+}
+
+// settle waits for the dump of c to stop changing, for a new snapshot.
+func settle(c fyne.Canvas) (string, image.Image) {
+	tree := dumpTree(c)
+	deadline := time.Now().Add(settleTimeout)
+	for stable := 0; stable < 5 && time.Now().Before(deadline); stable++ {
+		time.Sleep(10 * time.Millisecond)
+		if next := dumpTree(c); next != tree {
+			tree, stable = next, -1
+		}
+	}
+	return tree, c.Capture()
+}
+
+func writeSnapshot(imagePath string, treePath string, tree string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(treePath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(treePath, []byte(tree), 0o644); err != nil {
+		return err
+	}
+	return writePNG(imagePath, img)
+}
+
+func readPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return png.Decode(file)
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// diffImages returns nil if the images are equal, or else got faded with the
+// different pixels in red.
+func diffImages(expected image.Image, got image.Image) image.Image {
+	bounds := got.Bounds()
+	if expected.Bounds() != bounds {
+		return got
+	}
+
+	diff := image.NewNRGBA(bounds)
+	different := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			a := color.NRGBAModel.Convert(expected.At(x, y))
+			b := color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA)
+			if a != b {
+				different = true
+				diff.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+				continue
+			}
+			gray := uint8((uint16(b.R) + uint16(b.G) + uint16(b.B)) / 3)
+			diff.SetNRGBA(x, y, color.NRGBA{R: gray, G: gray, B: gray, A: 64})
+		}
+	}
+
+	if !different {
+		return nil
+	}
+	return diff
+}
+
+func firstDifference(expected string, got string) string {
+	expectedLines := strings.Split(expected, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(expectedLines) || i < len(gotLines); i++ {
+		var a, b string
+		if i < len(expectedLines) {
+			a = expectedLines[i]
+		}
+		if i < len(gotLines) {
+			b = gotLines[i]
+		}
+		if a != b {
+			return fmt.Sprintf("line %d:\n\texpected: %s\n\tgot:      %s", i+1, strings.TrimSpace(a), strings.TrimSpace(b))
+		}
+	}
+	return "no difference"
+}
+
+// dumpTree describes everything the canvas renders, one element per object,
+// with its position, size and content.
+func dumpTree(c fyne.Canvas) string {
+	var buf bytes.Buffer
+	size := c.Size()
+	fmt.Fprintf(&buf, "<canvas size=\"%gx%g\">\n", size.Width, size.Height)
+	dumpObject(&buf, c.Content(), 1)
+	for _, overlay := range c.Overlays().List() {
+		dumpObject(&buf, overlay, 1)
+	}
+	buf.WriteString("</canvas>\n")
+	return buf.String()
+}
+
+func dumpObject(buf *bytes.Buffer, obj fyne.CanvasObject, depth int) {
+	if obj == nil {
+		return
+	}
+
+	tag := strings.TrimPrefix(reflect.TypeOf(obj).String(), "*")
+	indent := strings.Repeat("\t", depth)
+
+	fmt.Fprintf(buf, "%s<%s pos=\"%g,%g\" size=\"%gx%g\"", indent, tag,
+		obj.Position().X, obj.Position().Y, obj.Size().Width, obj.Size().Height)
+	if !obj.Visible() {
+		buf.WriteString(" hidden=\"true\"")
+	}
+
+	var children []fyne.CanvasObject
+	switch obj := obj.(type) {
+	case *fyne.Container:
+		children = obj.Objects
+	case fyne.Widget:
+		children = test.WidgetRenderer(obj).Objects()
+	case *canvas.Text:
+		writeAttr(buf, "text", obj.Text)
+		writeAttr(buf, "color", colorString(obj.Color))
+		if obj.TextStyle.Bold {
+			buf.WriteString(" bold=\"true\"")
+		}
+		if obj.TextStyle.Italic {
+			buf.WriteString(" italic=\"true\"")
+		}
+	case *canvas.Rectangle:
+		writeAttr(buf, "fill", colorString(obj.FillColor))
+		if obj.StrokeWidth > 0 {
+			writeAttr(buf, "stroke", colorString(obj.StrokeColor))
+		}
+	case *canvas.Image:
+		if obj.Resource != nil {
+			writeAttr(buf, "resource", obj.Resource.Name())
+		} else if obj.File != "" {
+			writeAttr(buf, "file", filepath.Base(obj.File))
+		}
+	}
+
+	if len(children) == 0 {
+		buf.WriteString("/>\n")
+		return
+	}
+
+	buf.WriteString(">\n")
+	for _, child := range children {
+		dumpObject(buf, child, depth+1)
+	}
+	fmt.Fprintf(buf, "%s</%s>\n", indent, tag)
+}
+
+func writeAttr(buf *bytes.Buffer, name string, value string) {
+	fmt.Fprintf(buf, " %s=\"", name)
+	xml.EscapeText(buf, []byte(value))
+	buf.WriteString("\"")
+}
+
+func colorString(c color.Color) string {
+	if c == nil {
+		return ""
+	}
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B, nrgba.A)
+}
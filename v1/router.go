@@ -0,0 +1,401 @@
+package reago
+
+import (
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+)
+
+// Route is the location the router points at.
+type Route struct {
+	// Path is the full path, without the query, e.g. "/users/42".
+	Path   string
+	Params map[string]string
+	Query  map[string]string
+}
+
+// Router switches the content of <router> tags by path, keeping a
+// back/forward history. The current path is available in the state as
+// "route", and the params of the matched routes as "route.<name>".
+type Router struct {
+	dom     *DOM
+	history []*Route
+	index   int
+	guards  []func(from *Route, to *Route) bool
+	change  []func(*Route)
+	views   []*routerView
+	// keys are the "route.<name>" state values set for the current route
+	keys map[string]bool
+}
+
+// UseRouter returns the router of the DOM, creating it on first use. The
+// initial path is taken from the command line (--route=/path, or a deep link
+// of a scheme registered with UseDeepLinks), defaulting to "/".
+func (dom *DOM) UseRouter() *Router {
+	if dom.router == nil {
+		dom.router = &Router{dom: dom}
+		dom.router.push(parseRoute(deepLinkFromArgs(os.Args[1:], dom.deepLinks)))
+	}
+	return dom.router
+}
+
+// UseDeepLinks makes the router start at a deep link of one of the schemes
+// passed on the command line, e.g. myapp://users/42 goes to /users/42.
+func (dom *DOM) UseDeepLinks(schemes ...string) {
+	dom.deepLinks = append(dom.deepLinks, schemes...)
+	if dom.router != nil {
+		if path := deepLinkFromArgs(os.Args[1:], dom.deepLinks); path != "/" {
+			dom.router.Replace(path)
+		}
+	}
+}
+
+func deepLinkFromArgs(args []string, schemes []string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--route="); ok {
+			return value
+		}
+		if arg == "--route" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if u, err := url.Parse(arg); err == nil && u.Host != "" && slices.ContainsFunc(schemes, func(scheme string) bool {
+			return strings.EqualFold(scheme, u.Scheme)
+		}) {
+			path := "/" + u.Host + u.Path
+			if u.RawQuery != "" {
+				path += "?" + u.RawQuery
+			}
+			return path
+		}
+	}
+	return "/"
+}
+
+func parseRoute(path string) *Route {
+	route := &Route{Path: path, Params: map[string]string{}, Query: map[string]string{}}
+
+	if u, err := url.Parse(path); err == nil {
+		route.Path = u.Path
+		for key, values := range u.Query() {
+			route.Query[key] = values[0]
+		}
+	}
+	if !strings.HasPrefix(route.Path, "/") {
+		route.Path = "/" + route.Path
+	}
+
+	return route
+}
+
+func (router *Router) Current() *Route {
+	return router.history[router.index]
+}
+
+// Guard adds a check run before every navigation; returning false cancels it.
+func (router *Router) Guard(guard func(from *Route, to *Route) bool) {
+	router.guards = append(router.guards, guard)
+}
+
+func (router *Router) OnChange(callback func(*Route)) {
+	router.change = append(router.change, callback)
+}
+
+// Navigate goes to path, dropping any forward history. It returns false if a
+// guard rejected the navigation.
+func (router *Router) Navigate(path string) bool {
+	to := parseRoute(path)
+	if !router.allowed(to) {
+		return false
+	}
+
+	router.push(to)
+	router.update()
+	return true
+}
+
+// Replace goes to path, replacing the current history entry.
+func (router *Router) Replace(path string) bool {
+	to := parseRoute(path)
+	if !router.allowed(to) {
+		return false
+	}
+
+	router.history[router.index] = to
+	router.update()
+	return true
+}
+
+func (router *Router) CanGoBack() bool {
+	return router.index > 0
+}
+
+func (router *Router) CanGoForward() bool {
+	return router.index < len(router.history)-1
+}
+
+func (router *Router) Back() bool {
+	return router.Go(-1)
+}
+
+func (router *Router) Forward() bool {
+	return router.Go(1)
+}
+
+// Go moves delta entries through the history.
+func (router *Router) Go(delta int) bool {
+	index := router.index + delta
+	if index < 0 || index >= len(router.history) {
+		return false
+	}
+	if !router.allowed(router.history[index]) {
+		return false
+	}
+
+	router.index = index
+	router.update()
+	return true
+}
+
+func (router *Router) push(route *Route) {
+	if len(router.history) > 0 {
+		router.history = router.history[:router.index+1]
+	}
+	router.history = append(router.history, route)
+	router.index = len(router.history) - 1
+}
+
+func (router *Router) allowed(to *Route) bool {
+	from := router.Current()
+	for _, guard := range router.guards {
+		if !guard(from, to) {
+			return false
+		}
+	}
+	return true
+}
+
+func (router *Router) update() {
+	route := router.Current()
+
+	// views are re-rendered outer first, so nested views rendered in the
+	// process are not updated twice
+	for _, view := range append([]*routerView{}, router.views...) {
+		if view.mounted() {
+			view.render(route)
+		}
+	}
+
+	router.syncState(route)
+
+	for _, callback := range router.change {
+		callback(route)
+	}
+}
+
+// syncState sets "route" and "route.<name>" for the query and the params of
+// the matched routes, emptying the ones the route no longer has.
+func (router *Router) syncState(route *Route) {
+	state := router.dom.state
+	state.String("route", route.Path)
+
+	keys := make(map[string]bool)
+	for key, value := range route.Query {
+		keys["route."+key] = true
+		state.String("route."+key, value)
+	}
+	for _, view := range router.views {
+		for key, value := range view.params {
+			keys["route."+key] = true
+			state.String("route."+key, value)
+		}
+	}
+
+	for key := range router.keys {
+		if !keys[key] {
+			state.String(key, "")
+		}
+	}
+	router.keys = keys
+}
+
+func (router *Router) removeViews(views []*routerView) {
+	for _, view := range views {
+		view.removed = true
+		router.removeViews(view.children)
+	}
+
+	var kept []*routerView
+	for _, view := range router.views {
+		if !view.removed {
+			kept = append(kept, view)
+		}
+	}
+	router.views = kept
+}
+
+// routerView is a mounted <router> tag.
+type routerView struct {
+	router   *Router
+	dom      *DOM
+	node     *XMLNode
+	obj      *fyne.Container
+	base     string
+	parent   *routerView
+	children []*routerView
+	matched  *XMLNode
+	params   map[string]string
+	prefix   string
+	removed  bool
+	scope    *elementScope
+}
+
+func (view *routerView) mounted() bool {
+	return !view.removed
+}
+
+func (view *routerView) render(route *Route) {
+	dom := view.dom
+
+	var matched *XMLNode
+	var params map[string]string
+	var prefix string
+	for i := range view.node.Nodes {
+		child := &view.node.Nodes[i]
+		if child.GetTag() != "route" {
+			continue
+		}
+
+		// routes containing a nested <router> match by prefix
+		nested := hasNestedRouter(child)
+		if p, rest, ok := matchRoute(view.base+child.GetAttr("path"), route.Path, nested); ok {
+			matched, params, prefix = child, p, strings.TrimSuffix(route.Path, rest)
+			break
+		}
+	}
+
+	// nested routers take their base from the prefix, so they're rendered
+	// again when it changes
+	if matched != nil && matched == view.matched && prefix == view.prefix && maps.Equal(params, view.params) {
+		return
+	}
+	view.matched, view.params, view.prefix = matched, params, prefix
+
+	view.router.removeViews(view.children)
+	view.children = nil
+
+	if matched == nil {
+		if view.scope != nil {
+			view.scope.removed = true
+		}
+		view.obj.Objects = nil
+		view.obj.Refresh()
+		return
+	}
+
+	for key, value := range params {
+		dom.state.String("route."+key, value)
+	}
+
+	previousView, previousBase := dom.routeView, dom.routeBase
+	dom.routeView, dom.routeBase = view, prefix
+	dom.renderScope(&view.scope, func() {
+		view.obj.Objects = parseRouteContent(matched, dom)
+	})
+	dom.routeView, dom.routeBase = previousView, previousBase
+
+	view.obj.Refresh()
+}
+
+func parseRouteContent(node *XMLNode, dom *DOM) []fyne.CanvasObject {
+	if name := node.GetAttr("template"); name != "" {
+		content, ok := dom.templates[name]
+		if !ok {
+			return []fyne.CanvasObject{Parser.ParseXML("<label>unknown template: "+name+"</label>", dom)}
+		}
+		return []fyne.CanvasObject{Parser.ParseXML(content, dom)}
+	}
+	return Parser.ParseChildren(node, dom)
+}
+
+func hasNestedRouter(node *XMLNode) bool {
+	for i := range node.Nodes {
+		if node.Nodes[i].GetTag() == "router" || hasNestedRouter(&node.Nodes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoute matches path against a pattern such as "/users/:id". A "*"
+// segment matches the rest of the path. With prefix, the pattern only has to
+// match the start of path, and the unmatched rest is returned.
+func matchRoute(pattern string, path string, prefix bool) (map[string]string, string, bool) {
+	patternParts := splitPath(pattern)
+	pathParts := splitPath(path)
+	params := map[string]string{}
+
+	for i, part := range patternParts {
+		if part == "*" {
+			return params, "", true
+		}
+		if i >= len(pathParts) {
+			return nil, "", false
+		}
+		if name, ok := strings.CutPrefix(part, ":"); ok {
+			params[name] = pathParts[i]
+		} else if part != pathParts[i] {
+			return nil, "", false
+		}
+	}
+
+	rest := pathParts[len(patternParts):]
+	if len(rest) > 0 && !prefix {
+		return nil, "", false
+	}
+
+	if len(rest) == 0 {
+		return params, "", true
+	}
+	return params, "/" + strings.Join(rest, "/"), true
+}
+
+func splitPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// mountRouter creates the view of a <router> tag.
+func mountRouter(node *XMLNode, dom *DOM) fyne.CanvasObject {
+	router := dom.UseRouter()
+	view := &routerView{
+		router: router,
+		dom:    dom,
+		node:   node,
+		obj:    container.NewStack(),
+		base:   strings.TrimSuffix(dom.routeBase, "/"),
+		parent: dom.routeView,
+	}
+
+	if view.parent != nil {
+		view.parent.children = append(view.parent.children, view)
+	}
+	router.views = append(router.views, view)
+	dom.routerViews = append(slices.DeleteFunc(dom.routerViews, func(view *routerView) bool {
+		return view.removed
+	}), view)
+
+	view.render(router.Current())
+	router.syncState(router.Current())
+
+	return view.obj
+}
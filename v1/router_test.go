@@ -0,0 +1,146 @@
+package reago_test
+
+import (
+	"os"
+	"testing"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+const routerTemplate = `
+	<router>
+		<route path="/">
+			<label id="page">Home</label>
+		</route>
+		<route path="/users/:id">
+			<label id="page" bind:content="">User {{route.id}}</label>
+		</route>
+		<route path="/settings">
+			<router>
+				<route path="/:tab">
+					<label id="page" bind:content="">Settings {{route.tab}}</label>
+				</route>
+			</router>
+		</route>
+	</router>
+`
+
+func TestRouterNavigate(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.Template(routerTemplate)
+	h.Mount(dom)
+
+	router := dom.UseRouter()
+	h.AssertText("#page", "Home")
+
+	router.Navigate("/users/1")
+	h.AssertText("#page", "User 1")
+	h.AssertState("route", "/users/1")
+	h.AssertState("route.id", "1")
+
+	// the same route with other params is rendered again
+	router.Navigate("/users/2")
+	h.AssertText("#page", "User 2")
+	h.AssertState("route.id", "2")
+
+	router.Navigate("/settings/display")
+	h.AssertText("#page", "Settings display")
+	h.AssertState("route.tab", "display")
+
+	// the params of the routes left are emptied
+	h.AssertState("route.id", "")
+
+	router.Back()
+	h.AssertText("#page", "User 2")
+	h.AssertState("route.tab", "")
+
+	router.Forward()
+	h.AssertText("#page", "Settings display")
+}
+
+func TestRouterQuery(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.Template(routerTemplate)
+	h.Mount(dom)
+
+	router := dom.UseRouter()
+	router.Navigate("/users/3?tab=posts")
+	h.AssertState("route.tab", "posts")
+	if query := router.Current().Query["tab"]; query != "posts" {
+		t.Errorf("query tab is %q, expected posts", query)
+	}
+
+	router.Navigate("/users/3")
+	h.AssertState("route.tab", "")
+}
+
+func TestRouterGuard(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.Template(routerTemplate)
+	h.Mount(dom)
+
+	router := dom.UseRouter()
+	router.Guard(func(from *reago.Route, to *reago.Route) bool {
+		return to.Path != "/users/0"
+	})
+
+	if router.Navigate("/users/0") {
+		t.Error("navigation allowed, expected the guard to block it")
+	}
+	h.AssertText("#page", "Home")
+}
+
+func TestDeepLinks(t *testing.T) {
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+
+	for _, test := range []struct {
+		arg     string
+		schemes []string
+		path    string
+	}{
+		{"myapp://users/42", []string{"myapp"}, "/users/42"},
+		{"MyApp://users/42?tab=posts", []string{"myapp"}, "/users/42"},
+		{"https://example.com/users/42", []string{"myapp"}, "/"},
+		{"myapp://users/42", nil, "/"},
+		{"--route=/settings/display", nil, "/settings/display"},
+	} {
+		os.Args = []string{"app", test.arg}
+
+		dom := reago.NewDOM()
+		dom.UseDeepLinks(test.schemes...)
+		if path := dom.UseRouter().Current().Path; path != test.path {
+			t.Errorf("%s with schemes %v starts at %q, expected %q", test.arg, test.schemes, path, test.path)
+		}
+	}
+}
+
+func TestRouterKeepsViewsOfModalParent(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	dom.Template(routerTemplate)
+	h.Mount(dom)
+
+	modal := h.Window.Modal(`
+		<router>
+			<route path="/users/:id"><label id="modal">Modal {{route.id}}</label></route>
+		</router>
+	`, reago.ModalOptions{}, nil)
+	modal.Update(`<label>Updated</label>`)
+
+	router := dom.UseRouter()
+	router.Navigate("/users/1")
+	h.AssertText("#page", "User 1")
+
+	modal.Close()
+	router.Navigate("/users/2")
+	h.AssertText("#page", "User 2")
+}
@@ -0,0 +1,363 @@
+package reago
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+var keyAliases = map[string]fyne.KeyName{
+	"esc":       fyne.KeyEscape,
+	"escape":    fyne.KeyEscape,
+	"enter":     fyne.KeyReturn,
+	"return":    fyne.KeyReturn,
+	"tab":       fyne.KeyTab,
+	"backspace": fyne.KeyBackspace,
+	"insert":    fyne.KeyInsert,
+	"ins":       fyne.KeyInsert,
+	"delete":    fyne.KeyDelete,
+	"del":       fyne.KeyDelete,
+	"right":     fyne.KeyRight,
+	"left":      fyne.KeyLeft,
+	"down":      fyne.KeyDown,
+	"up":        fyne.KeyUp,
+	"pageup":    fyne.KeyPageUp,
+	"pgup":      fyne.KeyPageUp,
+	"pagedown":  fyne.KeyPageDown,
+	"pgdn":      fyne.KeyPageDown,
+	"home":      fyne.KeyHome,
+	"end":       fyne.KeyEnd,
+	"space":     fyne.KeySpace,
+	"plus":      fyne.KeyPlus,
+}
+
+var modifierAliases = map[string]fyne.KeyModifier{
+	"shift":     fyne.KeyModifierShift,
+	"ctrl":      fyne.KeyModifierControl,
+	"control":   fyne.KeyModifierControl,
+	"alt":       fyne.KeyModifierAlt,
+	"option":    fyne.KeyModifierAlt,
+	"super":     fyne.KeyModifierSuper,
+	"cmd":       fyne.KeyModifierSuper,
+	"command":   fyne.KeyModifierSuper,
+	"meta":      fyne.KeyModifierSuper,
+	"win":       fyne.KeyModifierSuper,
+	"mod":       fyne.KeyModifierShortcutDefault,
+	"cmdorctrl": fyne.KeyModifierShortcutDefault,
+}
+
+// ParseShortcut parses a key combination such as "Ctrl+S", "Ctrl+Shift+F5"
+// or "Mod+Enter" ("Mod" is Cmd on macOS and Ctrl elsewhere).
+func ParseShortcut(keys string) (*desktop.CustomShortcut, error) {
+	parts := strings.Split(strings.TrimSpace(keys), "+")
+
+	// "Ctrl++" ends with an empty part for the plus key itself
+	if len(parts) > 1 && parts[len(parts)-1] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	shortcut := &desktop.CustomShortcut{}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+
+		if i < len(parts)-1 {
+			mod, ok := modifierAliases[strings.ToLower(part)]
+			if !ok {
+				return nil, errors.New("unknown modifier: " + part)
+			}
+			shortcut.Modifier |= mod
+			continue
+		}
+
+		key, err := parseKeyName(part)
+		if err != nil {
+			return nil, err
+		}
+		shortcut.KeyName = key
+	}
+
+	return shortcut, nil
+}
+
+func parseKeyName(name string) (fyne.KeyName, error) {
+	if name == "" {
+		return fyne.KeyUnknown, errors.New("missing key")
+	}
+	if key, ok := keyAliases[strings.ToLower(name)]; ok {
+		return key, nil
+	}
+	if len(name) == 1 {
+		return fyne.KeyName(strings.ToUpper(name)), nil
+	}
+	if upper := strings.ToUpper(name); upper[0] == 'F' {
+		if n, err := strconv.Atoi(upper[1:]); err == nil && n >= 1 && n <= 12 {
+			return fyne.KeyName(upper), nil
+		}
+	}
+	return fyne.KeyUnknown, errors.New("unknown key: " + name)
+}
+
+type shortcutKind int
+
+const (
+	shortcutWindow shortcutKind = iota
+	shortcutTemplate
+	shortcutMenu
+)
+
+// shortcutBinding maps a key combination to a callback, or to the action of
+// a menu item. Scoped bindings only trigger while the focused object is
+// inside scope, and take precedence over unscoped ones.
+type shortcutBinding struct {
+	kind     shortcutKind
+	shortcut fyne.KeyboardShortcut
+	callback string
+	action   func()
+	node     *XMLNode
+	dom      *DOM
+	scope    fyne.CanvasObject
+}
+
+func (binding *shortcutBinding) name() string {
+	return binding.shortcut.ShortcutName()
+}
+
+func (binding *shortcutBinding) describe() string {
+	if binding.callback != "" {
+		return "callback \"" + binding.callback + "\""
+	}
+	return "a menu item"
+}
+
+func (binding *shortcutBinding) trigger(window *Window) {
+	if binding.action != nil {
+		binding.action()
+		return
+	}
+
+	dom := binding.dom
+	if dom == nil {
+		dom = window.dom
+	}
+	if dom != nil {
+		dom.invoke(binding.callback, binding.node)
+	}
+}
+
+// Shortcut binds a key combination (see ParseShortcut) to a callback
+// registered with UseCallback in the DOM shown in this window. Menu items
+// bound to the same callback display the shortcut.
+func (window *Window) Shortcut(keys string, callback string) error {
+	shortcut, err := ParseShortcut(keys)
+	if err != nil {
+		return err
+	}
+
+	binding := &shortcutBinding{kind: shortcutWindow, shortcut: shortcut, callback: callback}
+	if err := window.addShortcut(binding); err != nil {
+		return err
+	}
+
+	refresh := false
+	for _, item := range window.menuActions[callback] {
+		if item.Shortcut == nil {
+			item.Shortcut = shortcut
+			refresh = true
+		}
+	}
+	if refresh && window.w.MainMenu() != nil {
+		window.w.MainMenu().Refresh()
+	}
+
+	return nil
+}
+
+// RemoveShortcut removes a key combination bound with Shortcut.
+func (window *Window) RemoveShortcut(keys string) {
+	shortcut, err := ParseShortcut(keys)
+	if err != nil {
+		return
+	}
+	window.removeShortcuts(func(binding *shortcutBinding) bool {
+		return binding.kind == shortcutWindow && binding.name() == shortcut.ShortcutName()
+	})
+}
+
+func (window *Window) addShortcut(binding *shortcutBinding) error {
+	name := binding.name()
+
+	if binding.scope == nil {
+		for _, other := range window.shortcuts[name] {
+			if other.scope == nil {
+				return fmt.Errorf("shortcut %s is already bound to %s", name, other.describe())
+			}
+		}
+	}
+
+	if len(window.shortcuts[name]) == 0 {
+		window.w.Canvas().AddShortcut(binding.shortcut, func(fyne.Shortcut) {
+			window.triggerShortcut(name)
+		})
+	}
+	window.shortcuts[name] = append(window.shortcuts[name], binding)
+
+	return nil
+}
+
+func (window *Window) removeShortcuts(match func(*shortcutBinding) bool) {
+	for name, bindings := range window.shortcuts {
+		var kept []*shortcutBinding
+		for _, binding := range bindings {
+			if !match(binding) {
+				kept = append(kept, binding)
+			}
+		}
+
+		if len(kept) == 0 {
+			window.w.Canvas().RemoveShortcut(bindings[0].shortcut)
+			delete(window.shortcuts, name)
+		} else {
+			window.shortcuts[name] = kept
+		}
+	}
+}
+
+// syncShortcuts replaces the template shortcuts with the ones of the DOM
+// currently shown.
+func (window *Window) syncShortcuts() {
+	window.removeShortcuts(func(binding *shortcutBinding) bool {
+		return binding.kind == shortcutTemplate
+	})
+
+	if window.dom == nil {
+		return
+	}
+	for _, binding := range window.dom.shortcuts {
+		if err := window.addShortcut(binding); err != nil {
+			log.Println("shortcut error:", err)
+		}
+	}
+}
+
+// TypeShortcut runs the callback bound to a key combination, as if it was
+// typed.
+func (window *Window) TypeShortcut(keys string) error {
+	shortcut, err := ParseShortcut(keys)
+	if err != nil {
+		return err
+	}
+	if len(window.shortcuts[shortcut.ShortcutName()]) == 0 {
+		return errors.New("no callback is bound to " + keys)
+	}
+
+	window.triggerShortcut(shortcut.ShortcutName())
+	return nil
+}
+
+// triggerShortcut runs the innermost scoped binding containing the focused
+// object, or the unscoped binding if there is none. It reports whether a
+// binding ran.
+func (window *Window) triggerShortcut(name string) bool {
+	var focused fyne.CanvasObject
+	if obj, ok := window.w.Canvas().Focused().(fyne.CanvasObject); ok {
+		focused = obj
+	}
+
+	var match *shortcutBinding
+	for _, binding := range window.shortcuts[name] {
+		if binding.scope == nil {
+			if match == nil {
+				match = binding
+			}
+			continue
+		}
+		if !containsObject(binding.scope, focused) {
+			continue
+		}
+		if match == nil || match.scope == nil || containsObject(match.scope, binding.scope) {
+			match = binding
+		}
+	}
+
+	if match == nil {
+		return false
+	}
+	match.trigger(window)
+	return true
+}
+
+// containsObject reports whether obj is shown inside scope, walking the
+// objects scope is made of. Objects in popups and overlays are never inside.
+//
+// The items of lists and trees live in their renderers, so objects created by
+// ReaGO are also inside when the list or tree rendering them is.
+func containsObject(scope fyne.CanvasObject, obj fyne.CanvasObject) bool {
+	if obj == nil || !scope.Visible() {
+		return false
+	}
+
+	targets := []fyne.CanvasObject{obj}
+	if owned, ok := obj.(interface{ ownerDOM() *DOM }); ok && owned.ownerDOM() != nil {
+		targets = append(targets, owned.ownerDOM().owners...)
+	}
+
+	var walk func(fyne.CanvasObject) bool
+	walk = func(current fyne.CanvasObject) bool {
+		if slices.Contains(targets, current) {
+			return true
+		}
+		for _, child := range childObjects(current) {
+			if child != nil && child.Visible() && walk(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(scope)
+}
+
+// childObjects returns the objects obj is made of, for the containers and
+// widgets the tags create.
+func childObjects(obj fyne.CanvasObject) []fyne.CanvasObject {
+	switch obj := obj.(type) {
+	case *fyne.Container:
+		return obj.Objects
+	case *container.Scroll:
+		return []fyne.CanvasObject{obj.Content}
+	case *container.Split:
+		return []fyne.CanvasObject{obj.Leading, obj.Trailing}
+	case *container.AppTabs:
+		var children []fyne.CanvasObject
+		for _, item := range obj.Items {
+			children = append(children, item.Content)
+		}
+		return children
+	case *widget.Accordion:
+		var children []fyne.CanvasObject
+		for _, item := range obj.Items {
+			if item.Open {
+				children = append(children, item.Detail)
+			}
+		}
+		return children
+	case *widget.Card:
+		return []fyne.CanvasObject{obj.Content}
+	case *widget.Toolbar:
+		var children []fyne.CanvasObject
+		for _, item := range obj.Items {
+			children = append(children, item.ToolbarObject())
+		}
+		return children
+	case interface{ wrapped() fyne.CanvasObject }:
+		return []fyne.CanvasObject{obj.wrapped()}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package reago
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestContainsObjectInListItems(t *testing.T) {
+	test.NewTempApp(t)
+
+	dom := NewDOM()
+	dom.UseState().List("rows", []any{"a"})
+	dom.UseCallback("save", func(node *XMLNode) {})
+	dom.Template(`
+		<col>
+			<shortcut keys="Ctrl+S" bind:trigger="save">
+				<list id="rows" bind:items="rows"><input /></list>
+			</shortcut>
+			<list id="others" bind:items="rows"><input /></list>
+		</col>
+	`)
+	test.NewTempWindow(t, dom.root).Resize(fyne.NewSize(400, 400))
+
+	scope := dom.shortcuts[0].scope
+	entry := func(id string) fyne.CanvasObject {
+		for _, obj := range test.LaidOutObjects(dom.GetVirtualList(id)) {
+			if entry, ok := obj.(*inputEntry); ok {
+				return entry
+			}
+		}
+		t.Fatalf("the %s list has no entry", id)
+		return nil
+	}
+
+	if !containsObject(scope, entry("rows")) {
+		t.Error("an entry of a list inside the scope is not inside it")
+	}
+	if containsObject(scope, entry("others")) {
+		t.Error("an entry of a list outside the scope is inside it")
+	}
+}
@@ -0,0 +1,57 @@
+package reago_test
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+func TestShortcutScopes(t *testing.T) {
+	h := reagotest.New(t)
+
+	dom := reago.NewDOM()
+	triggered := dom.UseState().String("triggered", "")
+	for _, name := range []string{"global", "outer", "inner"} {
+		dom.UseCallback(name, func(node *reago.XMLNode) {
+			triggered.Set(name)
+		})
+	}
+	dom.Template(`
+		<col>
+			<shortcut keys="Ctrl+S" bind:trigger="global" />
+			<input id="outside" />
+			<shortcut keys="Ctrl+S" bind:trigger="outer">
+				<col>
+					<input id="editor" />
+					<shortcut keys="Ctrl+S" bind:trigger="inner">
+						<input id="search" />
+					</shortcut>
+				</col>
+			</shortcut>
+		</col>
+	`)
+	h.Mount(dom)
+
+	expect := func(focused fyne.Focusable, expected string) {
+		t.Helper()
+		triggered.Set("")
+		h.Window.Canvas().Focus(focused)
+		h.Shortcut("Ctrl+S")
+		if got := triggered.Get(); got != expected {
+			t.Errorf("Ctrl+S with %T focused triggered %q, expected %q", focused, got, expected)
+		}
+	}
+
+	expect(h.Find("#outside").(fyne.Focusable), "global")
+	expect(h.Find("#editor").(fyne.Focusable), "outer")
+	expect(h.Find("#search").(fyne.Focusable), "inner")
+
+	// a popup over the scope isn't inside it
+	popup := widget.NewModalPopUp(widget.NewEntry(), h.Window.Canvas())
+	popup.ShowAtPosition(fyne.CurrentApp().Driver().AbsolutePositionForObject(dom.GetInput("editor")))
+	expect(popup.Content.(*widget.Entry), "global")
+}
@@ -1,12 +1,17 @@
 package reago
 
 import (
+	"maps"
+	"reflect"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/data/binding"
 )
 
 type State struct {
 	binds map[string]IReactive
+	// snapshot holds the values of a copy when it was made (see copy)
+	snapshot map[string]any
 }
 
 func NewState() *State {
@@ -138,3 +143,149 @@ func (state *State) List(name string, value []any) *ReactiveList[any] {
 	bind.Set(value)
 	return bind
 }
+
+func (state *State) GetTree(name string) *ReactiveTree[any] {
+	var bind *ReactiveTree[any]
+	if reactive, ok := state.binds[name]; ok {
+		bind = reactive.(*ReactiveTree[any])
+	} else {
+		bind = NewReactiveTree[any](binding.NewUntypedTree())
+		state.binds[name] = bind
+	}
+	return bind
+}
+
+func (state *State) Tree(name string, ids map[string][]string, values map[string]any) *ReactiveTree[any] {
+	bind := state.GetTree(name)
+	bind.Set(ids, values)
+	return bind
+}
+
+// Value returns the current value of any kind of reactive in the state, and
+// whether it exists.
+func (state *State) Value(name string) (any, bool) {
+	switch reactive := state.binds[name].(type) {
+	case *Reactive[bool]:
+		return reactive.Get(), true
+	case *Reactive[[]byte]:
+		return reactive.Get(), true
+	case *Reactive[float64]:
+		return reactive.Get(), true
+	case *Reactive[int]:
+		return reactive.Get(), true
+	case *Reactive[string]:
+		return reactive.Get(), true
+	case *Reactive[fyne.URI]:
+		return reactive.Get(), true
+	case *ReactiveList[any]:
+		return reactive.Get(), true
+	case *ReactiveTree[any]:
+		_, values := reactive.Get()
+		return values, true
+	}
+	return nil, false
+}
+
+// copy returns a state with copies of the values of state, so they can be
+// edited without changing state until written back with writeTo.
+func (state *State) copy() *State {
+	clone := NewState()
+	clone.snapshot = make(map[string]any)
+	for name, reactive := range state.binds {
+		switch reactive := reactive.(type) {
+		case *Reactive[bool]:
+			clone.Bool(name, reactive.Get())
+		case *Reactive[[]byte]:
+			clone.Bytes(name, reactive.Get())
+		case *Reactive[float64]:
+			clone.Float(name, reactive.Get())
+		case *Reactive[int]:
+			clone.Int(name, reactive.Get())
+		case *Reactive[string]:
+			clone.String(name, reactive.Get())
+		case *Reactive[fyne.URI]:
+			clone.URI(name, reactive.Get())
+		case *ReactiveList[any]:
+			clone.List(name, reactive.Get())
+		case *ReactiveTree[any]:
+			ids, values := reactive.Get()
+			clone.Tree(name, maps.Clone(ids), values)
+		default:
+			clone.binds[name] = reactive
+			continue
+		}
+		clone.snapshot[name] = snapshotOf(clone.binds[name])
+	}
+	return clone
+}
+
+// snapshotOf returns the value of a reactive, to tell whether it changed.
+func snapshotOf(reactive IReactive) any {
+	switch reactive := reactive.(type) {
+	case *Reactive[bool]:
+		return reactive.Get()
+	case *Reactive[[]byte]:
+		return reactive.Get()
+	case *Reactive[float64]:
+		return reactive.Get()
+	case *Reactive[int]:
+		return reactive.Get()
+	case *Reactive[string]:
+		return reactive.Get()
+	case *Reactive[fyne.URI]:
+		return reactive.Get()
+	case *ReactiveList[any]:
+		return reactive.Get()
+	case *ReactiveTree[any]:
+		ids, values := reactive.Get()
+		return [2]any{ids, values}
+	}
+	return nil
+}
+
+// writeTo sets in target the values of state, a copy, that changed since it
+// was copied, skipping the ones target doesn't have. Values of target that
+// changed in the meantime are kept unless the copy changed them too.
+func (state *State) writeTo(target *State) {
+	for name, reactive := range state.binds {
+		before, ok := state.snapshot[name]
+		if !ok || reflect.DeepEqual(before, snapshotOf(reactive)) {
+			continue
+		}
+
+		switch reactive := reactive.(type) {
+		case *Reactive[bool]:
+			if to, ok := target.binds[name].(*Reactive[bool]); ok {
+				to.Set(reactive.Get())
+			}
+		case *Reactive[[]byte]:
+			if to, ok := target.binds[name].(*Reactive[[]byte]); ok {
+				to.Set(reactive.Get())
+			}
+		case *Reactive[float64]:
+			if to, ok := target.binds[name].(*Reactive[float64]); ok {
+				to.Set(reactive.Get())
+			}
+		case *Reactive[int]:
+			if to, ok := target.binds[name].(*Reactive[int]); ok {
+				to.Set(reactive.Get())
+			}
+		case *Reactive[string]:
+			if to, ok := target.binds[name].(*Reactive[string]); ok {
+				to.Set(reactive.Get())
+			}
+		case *Reactive[fyne.URI]:
+			if to, ok := target.binds[name].(*Reactive[fyne.URI]); ok {
+				to.Set(reactive.Get())
+			}
+		case *ReactiveList[any]:
+			if to, ok := target.binds[name].(*ReactiveList[any]); ok {
+				to.Set(reactive.Get())
+			}
+		case *ReactiveTree[any]:
+			if to, ok := target.binds[name].(*ReactiveTree[any]); ok {
+				to.Set(reactive.Get())
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package reago
+
+import "testing"
+
+func TestStateCopyWritesBackOnlyOnWriteTo(t *testing.T) {
+	state := NewState()
+	state.String("name", "Ada")
+	state.List("tags", []any{"a"})
+
+	edited := state.copy()
+	edited.String("name", "Grace")
+	edited.GetList("tags").Append("b")
+	edited.Int("scoped", 1)
+
+	if got := state.GetString("name").Get(); got != "Ada" {
+		t.Fatalf("name = %q before writeTo, want Ada", got)
+	}
+	if got := len(state.GetList("tags").Get()); got != 1 {
+		t.Fatalf("%d tags before writeTo, want 1", got)
+	}
+
+	edited.writeTo(state)
+
+	if got := state.GetString("name").Get(); got != "Grace" {
+		t.Errorf("name = %q, want Grace", got)
+	}
+	if got := len(state.GetList("tags").Get()); got != 2 {
+		t.Errorf("%d tags, want 2", got)
+	}
+	if state.Has("scoped") {
+		t.Error("a value created in the copy was written back")
+	}
+}
+
+func TestStateWriteToKeepsValuesChangedMeanwhile(t *testing.T) {
+	state := NewState()
+	state.String("name", "Ada")
+	state.String("route", "/")
+	state.Int("count", 1)
+
+	edited := state.copy()
+	edited.String("name", "Grace")
+
+	// the parent changes while the copy is edited, e.g. from a handler
+	state.String("route", "/users")
+	state.Int("count", 2)
+
+	edited.writeTo(state)
+
+	if got := state.GetString("name").Get(); got != "Grace" {
+		t.Errorf("name = %q, want Grace", got)
+	}
+	if got := state.GetString("route").Get(); got != "/users" {
+		t.Errorf("route = %q, want /users, changed while the copy was edited", got)
+	}
+	if got := state.GetInt("count").Get(); got != 2 {
+		t.Errorf("count = %d, want 2, changed while the copy was edited", got)
+	}
+}
+
+func TestTreeEmptyFolderIsBranch(t *testing.T) {
+	tree := NewState().Tree("files", map[string][]string{
+		"":     {"docs", "empty", "readme"},
+		"docs": {"guide"},
+		// a folder without files
+		"empty": {},
+	}, map[string]any{"docs": "docs", "guide": "guide", "empty": "empty", "readme": "readme"})
+
+	for id, branch := range map[string]bool{"docs": true, "empty": true, "guide": false, "readme": false} {
+		if tree.IsBranch(id) != branch {
+			t.Errorf("IsBranch(%q) = %v, expected %v", id, !branch, branch)
+		}
+	}
+}
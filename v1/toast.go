@@ -0,0 +1,248 @@
+package reago
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// ToastAction is a button on a toast. It runs Action, or the DOM callback
+// named Bind, then dismisses the toast.
+type ToastAction struct {
+	Label  string
+	Action func()
+	Bind   string
+}
+
+type ToastOptions struct {
+	Level ToastLevel
+	// Duration is how long the toast stays, 4 seconds by default. A negative
+	// duration keeps it until dismissed.
+	Duration time.Duration
+	Actions  []ToastAction
+	// Notify also sends the message as a desktop notification, with Title.
+	Notify bool
+	Title  string
+}
+
+const defaultToastDuration = 4 * time.Second
+
+// Toast is a shown, or queued, notification.
+type Toast struct {
+	host    *toastHost
+	obj     fyne.CanvasObject
+	timer   Timer
+	options ToastOptions
+}
+
+// Dismiss removes the toast, showing the next queued one.
+func (toast *Toast) Dismiss() {
+	toast.host.remove(toast)
+}
+
+// toastHost stacks the toasts of a <toasts> tag, or of a window without one.
+// Toasts over max wait in a queue. It can be used from any goroutine.
+type toastHost struct {
+	lock    sync.Mutex
+	box     *fyne.Container
+	obj     fyne.CanvasObject
+	max     int
+	shown   []*Toast
+	queue   []*Toast
+	dom     *DOM
+	topDown bool
+}
+
+// newToastHost creates a host aligned to position, e.g. "bottom-right" (the
+// default), "top" or "top-left".
+func newToastHost(position string, max int, dom *DOM) *toastHost {
+	host := &toastHost{box: container.NewVBox(), max: max, dom: dom}
+	if host.max <= 0 {
+		host.max = 5
+	}
+
+	var row fyne.CanvasObject
+	switch {
+	case position == "top-left" || position == "bottom-left":
+		row = container.NewHBox(host.box, layout.NewSpacer())
+	case position == "top" || position == "bottom":
+		row = container.NewHBox(layout.NewSpacer(), host.box, layout.NewSpacer())
+	default:
+		row = container.NewHBox(layout.NewSpacer(), host.box)
+	}
+
+	if position == "top" || position == "top-left" || position == "top-right" {
+		host.topDown = true
+		host.obj = container.NewBorder(row, nil, nil, nil)
+	} else {
+		host.obj = container.NewBorder(nil, row, nil, nil)
+	}
+
+	return host
+}
+
+func (host *toastHost) show(message string, opts ToastOptions) *Toast {
+	toast := &Toast{host: host, options: opts}
+	toast.obj = host.render(toast, message)
+
+	host.lock.Lock()
+	if len(host.shown) >= host.max {
+		host.queue = append(host.queue, toast)
+		host.lock.Unlock()
+		return toast
+	}
+	host.display(toast)
+	host.lock.Unlock()
+
+	host.box.Refresh()
+	return toast
+}
+
+// display adds toast to the box and starts its timer. The lock must be held.
+func (host *toastHost) display(toast *Toast) {
+	host.shown = append(host.shown, toast)
+	if host.topDown {
+		host.box.Objects = append(host.box.Objects, toast.obj)
+	} else {
+		host.box.Objects = append([]fyne.CanvasObject{toast.obj}, host.box.Objects...)
+	}
+
+	duration := toast.options.Duration
+	if duration == 0 {
+		duration = defaultToastDuration
+	}
+	if duration > 0 {
+		toast.timer = currentClock().AfterFunc(duration, toast.Dismiss)
+	}
+}
+
+func (host *toastHost) remove(toast *Toast) {
+	host.lock.Lock()
+
+	for i, queued := range host.queue {
+		if queued == toast {
+			host.queue = append(host.queue[:i:i], host.queue[i+1:]...)
+			host.lock.Unlock()
+			return
+		}
+	}
+
+	found := false
+	for i, shown := range host.shown {
+		if shown == toast {
+			host.shown = append(host.shown[:i:i], host.shown[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		host.lock.Unlock()
+		return
+	}
+
+	if toast.timer != nil {
+		toast.timer.Stop()
+	}
+
+	var objects []fyne.CanvasObject
+	for _, obj := range host.box.Objects {
+		if obj != toast.obj {
+			objects = append(objects, obj)
+		}
+	}
+	host.box.Objects = objects
+
+	if len(host.queue) > 0 {
+		next := host.queue[0]
+		host.queue = host.queue[1:]
+		host.display(next)
+	}
+
+	host.lock.Unlock()
+	host.box.Refresh()
+}
+
+func (host *toastHost) render(toast *Toast, message string) fyne.CanvasObject {
+	var stripe color.Color
+	var icon fyne.Resource
+	switch toast.options.Level {
+	case ToastSuccess:
+		stripe, icon = theme.Color(theme.ColorNameSuccess), theme.ConfirmIcon()
+	case ToastWarning:
+		stripe, icon = theme.Color(theme.ColorNameWarning), theme.WarningIcon()
+	case ToastError:
+		stripe, icon = theme.Color(theme.ColorNameError), theme.ErrorIcon()
+	default:
+		stripe, icon = theme.Color(theme.ColorNamePrimary), theme.InfoIcon()
+	}
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	background.StrokeColor = theme.Color(theme.ColorNameShadow)
+	background.StrokeWidth = 1
+	background.CornerRadius = theme.InputRadiusSize()
+	background.SetMinSize(fyne.NewSize(280, 0))
+
+	bar := canvas.NewRectangle(stripe)
+	bar.SetMinSize(fyne.NewSize(4, 0))
+
+	label := widget.NewLabel(message)
+	label.Wrapping = fyne.TextWrapWord
+
+	var buttons []fyne.CanvasObject
+	for _, action := range toast.options.Actions {
+		action := action
+		button := widget.NewButton(action.Label, func() {
+			if action.Action != nil {
+				action.Action()
+			}
+			if action.Bind != "" && host.dom != nil {
+				host.dom.dispatch(action.Bind, &Event{Type: "toast"})
+			}
+			toast.Dismiss()
+		})
+		button.Importance = widget.LowImportance
+		buttons = append(buttons, button)
+	}
+
+	closeButton := widget.NewButtonWithIcon("", theme.CancelIcon(), toast.Dismiss)
+	closeButton.Importance = widget.LowImportance
+
+	body := container.NewBorder(nil, nil,
+		container.NewHBox(bar, widget.NewIcon(icon)),
+		container.NewHBox(append(buttons, closeButton)...),
+		label,
+	)
+
+	return container.NewStack(background, body)
+}
+
+// Toast shows a transient notification in the <toasts> tag of the shown DOM,
+// or in the bottom right corner of the window. It can be called from any
+// goroutine.
+func (window *Window) Toast(message string, opts ToastOptions) *Toast {
+	if opts.Notify {
+		window.app.app.SendNotification(fyne.NewNotification(opts.Title, message))
+	}
+
+	host := window.toasts
+	if window.dom != nil && window.dom.toasts != nil {
+		host = window.dom.toasts
+	}
+	return host.show(message, opts)
+}
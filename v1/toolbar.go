@@ -0,0 +1,153 @@
+package reago
+
+import (
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const tooltipDelay = 500 * time.Millisecond
+
+// ToolbarAction is the toolbar button created by <action>. Unlike
+// widget.ToolbarAction it is a canvas object itself, so it can be stored in
+// DOM refs, and it supports tooltips and a toggled (active) state.
+type ToolbarAction struct {
+	widget.Button
+
+	Tooltip     string
+	Toggle      bool
+	OnActivated func()
+	OnToggled   func(bool)
+
+	active  bool
+	pointer pointerTrack
+
+	// lock guards the tooltip, shown by a timer
+	lock    sync.Mutex
+	tooltip *widget.PopUp
+	timer   Timer
+}
+
+var _ widget.ToolbarItem = (*ToolbarAction)(nil)
+var _ desktop.Hoverable = (*ToolbarAction)(nil)
+
+func NewToolbarAction(icon fyne.Resource, onActivated func()) *ToolbarAction {
+	action := &ToolbarAction{OnActivated: onActivated}
+	action.Icon = icon
+	action.Importance = widget.LowImportance
+	action.OnTapped = action.tapped
+	action.ExtendBaseWidget(action)
+	return action
+}
+
+func (action *ToolbarAction) ToolbarObject() fyne.CanvasObject {
+	return action
+}
+
+func (action *ToolbarAction) IsActive() bool {
+	return action.active
+}
+
+func (action *ToolbarAction) SetActive(value bool) {
+	if action.active == value {
+		return
+	}
+
+	action.active = value
+	if value {
+		action.Importance = widget.HighImportance
+	} else {
+		action.Importance = widget.LowImportance
+	}
+	action.Refresh()
+
+	if action.OnToggled != nil {
+		action.OnToggled(value)
+	}
+}
+
+func (action *ToolbarAction) tapped() {
+	action.hideTooltip()
+
+	if action.Toggle {
+		action.SetActive(!action.active)
+	}
+	if action.OnActivated != nil {
+		action.OnActivated()
+	}
+}
+
+func (action *ToolbarAction) MouseIn(event *desktop.MouseEvent) {
+	action.Button.MouseIn(event)
+	action.pointer.in(action, event)
+
+	if action.Tooltip == "" {
+		return
+	}
+	driver := fyne.CurrentApp().Driver()
+	canvas := driver.CanvasForObject(action)
+	if canvas == nil {
+		return
+	}
+
+	// the popup is built here, on the UI thread. The timer shows it from its
+	// own goroutine, which fyne 2.5 allows for Show and Hide.
+	tooltip := widget.NewPopUp(widget.NewLabel(action.Tooltip), canvas)
+	pos := driver.AbsolutePositionForObject(action).AddXY(0, action.Size().Height)
+
+	action.lock.Lock()
+	defer action.lock.Unlock()
+
+	action.stopTooltip()
+	action.tooltip = tooltip
+	action.timer = currentClock().AfterFunc(tooltipDelay, func() {
+		action.lock.Lock()
+		defer action.lock.Unlock()
+
+		if action.tooltip == tooltip {
+			action.timer = nil
+			tooltip.ShowAtPosition(pos)
+		}
+	})
+}
+
+func (action *ToolbarAction) MouseMoved(event *desktop.MouseEvent) {
+	action.Button.MouseMoved(event)
+	action.pointer.moved(event)
+}
+
+func (action *ToolbarAction) MouseOut() {
+	action.Button.MouseOut()
+	action.pointer.out()
+	action.hideTooltip()
+}
+
+func (action *ToolbarAction) hideTooltip() {
+	action.lock.Lock()
+	defer action.lock.Unlock()
+	action.stopTooltip()
+}
+
+// stopTooltip stops the timer and hides the tooltip. The lock must be held.
+func (action *ToolbarAction) stopTooltip() {
+	if action.timer != nil {
+		action.timer.Stop()
+		action.timer = nil
+	}
+	if action.tooltip != nil {
+		action.tooltip.Hide()
+		action.tooltip = nil
+	}
+}
+
+// toolbarItem wraps a custom template inside a toolbar.
+type toolbarItem struct {
+	obj fyne.CanvasObject
+}
+
+func (item *toolbarItem) ToolbarObject() fyne.CanvasObject {
+	return item.obj
+}
@@ -0,0 +1,183 @@
+package reago
+
+import (
+	"errors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// Tray is the icon and menu of the app in the system tray.
+type Tray struct {
+	app     *App
+	desk    desktop.App
+	menu    *fyne.Menu
+	toggles []*trayToggle
+	tooltip string
+}
+
+// trayToggle is a menu item that shows or hides a window.
+type trayToggle struct {
+	window *Window
+	item   *fyne.MenuItem
+}
+
+// Tray shows the app in the system tray, with icon (anything Icons can
+// resolve) and menu. It returns an error when the driver has no system tray,
+// e.g. on mobile.
+func (a *App) Tray(icon string, menu *fyne.Menu) (*Tray, error) {
+	desk, ok := a.app.(desktop.App)
+	if !ok {
+		return nil, errors.New("system tray is not supported by this driver")
+	}
+
+	if menu == nil {
+		menu = fyne.NewMenu("")
+	}
+
+	tray := &Tray{app: a, desk: desk, menu: menu}
+	if icon != "" {
+		tray.SetIcon(icon)
+	}
+	desk.SetSystemTrayMenu(menu)
+
+	return tray, nil
+}
+
+// TrayTemplate creates the tray from a <tray> template, bound to the DOM state
+// and callbacks:
+//
+//	<tray icon="app.png" bind:tooltip="status">
+//		<item window="main" />
+//		<separator />
+//		<item bind:click="sync">Sync now</item>
+//		<item quit="true">Quit</item>
+//	</tray>
+//
+// An item with a window attribute shows or hides the window with that id.
+func (a *App) TrayTemplate(dom *DOM, content string) (*Tray, error) {
+	root, err := Parser.parseRoot(content, dom.parseMode)
+	if err != nil {
+		return nil, err
+	}
+	if root.GetTag() != "tray" {
+		return nil, errors.New("tray template must have a <tray> root")
+	}
+
+	tray, err := a.Tray("", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := func() {
+		tray.menu.Refresh()
+	}
+
+	for i := range root.Nodes {
+		node := &root.Nodes[i]
+
+		if id := node.GetAttr("window"); id != "" && node.GetTag() == "item" {
+			window := a.Window(id)
+			if window == nil {
+				return nil, errors.New("tray item for unknown window: " + id)
+			}
+			tray.menu.Items = append(tray.menu.Items, tray.toggleItem(window))
+			continue
+		}
+
+		if item := parseMenuItem(node, dom, nil, refresh); item != nil {
+			tray.menu.Items = append(tray.menu.Items, item)
+		}
+	}
+
+	root.BindString("icon", dom, func(value string) {
+		if value != "" {
+			tray.SetIcon(value)
+		}
+	})
+	root.BindString("tooltip", dom, tray.SetTooltip)
+
+	tray.menu.Refresh()
+
+	return tray, nil
+}
+
+func (tray *Tray) SetIcon(name string) {
+	if icon := Icons.Resolve(name); icon != nil {
+		tray.desk.SetSystemTrayIcon(icon)
+	}
+}
+
+func (tray *Tray) SetTooltip(tooltip string) {
+	tray.tooltip = tooltip
+	setTrayTooltip(tooltip)
+}
+
+func (tray *Tray) GetTooltip() string {
+	return tray.tooltip
+}
+
+// BindIcon keeps the icon in sync with a state value.
+func (tray *Tray) BindIcon(value *Reactive[string]) {
+	tray.SetIcon(value.Get())
+	value.OnChange(tray.SetIcon)
+}
+
+// BindTooltip keeps the tooltip in sync with a state value.
+func (tray *Tray) BindTooltip(value *Reactive[string]) {
+	tray.SetTooltip(value.Get())
+	value.OnChange(tray.SetTooltip)
+}
+
+func (tray *Tray) GetMenu() *fyne.Menu {
+	return tray.menu
+}
+
+func (tray *Tray) SetMenu(menu *fyne.Menu) {
+	tray.menu = menu
+	tray.desk.SetSystemTrayMenu(menu)
+}
+
+// ToggleWindow adds a "Show"/"Hide" item for the window at the top of the
+// menu. Closing the window then hides it to the tray instead.
+func (tray *Tray) ToggleWindow(window *Window) {
+	tray.menu.Items = append([]*fyne.MenuItem{tray.toggleItem(window)}, tray.menu.Items...)
+	tray.menu.Refresh()
+}
+
+func (tray *Tray) toggleItem(window *Window) *fyne.MenuItem {
+	toggle := &trayToggle{window: window, item: fyne.NewMenuItem("", nil)}
+	toggle.item.Action = func() {
+		if window.IsHidden() {
+			window.Reveal()
+		} else {
+			window.Hide()
+		}
+		tray.refreshToggles()
+	}
+	tray.toggles = append(tray.toggles, toggle)
+	toggle.update()
+
+	window.hideOnClose = func() {
+		window.Hide()
+		tray.refreshToggles()
+	}
+	window.w.SetCloseIntercept(window.interceptClose)
+
+	return toggle.item
+}
+
+func (tray *Tray) refreshToggles() {
+	for _, toggle := range tray.toggles {
+		toggle.update()
+	}
+	tray.menu.Refresh()
+}
+
+func (toggle *trayToggle) update() {
+	if toggle.window.IsHidden() {
+		toggle.item.Label = "Show " + toggle.window.GetTitle()
+	} else {
+		toggle.item.Label = "Hide " + toggle.window.GetTitle()
+	}
+}
@@ -0,0 +1,9 @@
+//go:build (linux || freebsd || openbsd || netbsd || darwin || windows) && !android && !ios && !wasm && !mobile
+
+package reago
+
+import "fyne.io/systray"
+
+func setTrayTooltip(tooltip string) {
+	systray.SetTooltip(tooltip)
+}
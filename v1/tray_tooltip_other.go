@@ -0,0 +1,6 @@
+//go:build !(linux || freebsd || openbsd || netbsd || darwin || windows) || android || ios || wasm || mobile
+
+package reago
+
+// setTrayTooltip does nothing where there is no system tray.
+func setTrayTooltip(string) {}
@@ -0,0 +1,319 @@
+package reago
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// reloadDelay is how long a file must stay unchanged before it is read,
+	// so that the many events of a save trigger a single reload.
+	reloadDelay = 100 * time.Millisecond
+	// reloadRetries is how many times a missing file is looked for again, as
+	// editors saving atomically remove it before renaming the new one.
+	reloadRetries = 10
+)
+
+// Watcher hot reloads the files of a DOM. It watches their directories, so
+// that it keeps working when editors replace a file on save. A file that
+// fails to load is reported over the UI, which is kept as it was. State is
+// kept across reloads.
+type Watcher struct {
+	watcher  *fsnotify.Watcher
+	callback func(filename string)
+
+	lock    sync.Mutex
+	files   map[string]bool
+	dirs    map[string]bool
+	pending map[string]Timer
+	done    bool
+
+	// reload makes the callbacks run one at a time
+	reload sync.Mutex
+}
+
+func newWatcher(callback func(filename string)) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		watcher:  watcher,
+		callback: callback,
+		files:    make(map[string]bool),
+		dirs:     make(map[string]bool),
+		pending:  make(map[string]Timer),
+	}
+	go w.run()
+	Icons.watch(w)
+	return w, nil
+}
+
+// Stop stops watching. It can be called on a nil watcher.
+func (w *Watcher) Stop() {
+	if w == nil {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.done {
+		return
+	}
+	w.done = true
+	Icons.unwatch(w)
+	for _, timer := range w.pending {
+		timer.Stop()
+	}
+	w.pending = nil
+	w.watcher.Close()
+}
+
+func (w *Watcher) stopped() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.done
+}
+
+func (w *Watcher) add(filename string) error {
+	absPath, err := filepath.Abs(filename)
+	if err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.done {
+		return nil
+	}
+
+	dir := filepath.Dir(absPath)
+	if !w.dirs[dir] {
+		if err := w.watcher.Add(dir); err != nil {
+			return err
+		}
+		w.dirs[dir] = true
+	}
+	w.files[absPath] = true
+	return nil
+}
+
+func (w *Watcher) run() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			// Compare absolute paths to ensure a match.
+			eventPath, err := filepath.Abs(event.Name)
+			if err != nil {
+				continue
+			}
+			w.schedule(eventPath, 0)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("Watcher error:", err)
+		}
+	}
+}
+
+// schedule reloads filename once it stopped changing for reloadDelay.
+func (w *Watcher) schedule(filename string, retry int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.done || !w.files[filename] {
+		return
+	}
+
+	if timer, ok := w.pending[filename]; ok {
+		timer.Stop()
+	}
+	w.pending[filename] = currentClock().AfterFunc(reloadDelay, func() {
+		w.lock.Lock()
+		if w.done {
+			w.lock.Unlock()
+			return
+		}
+		delete(w.pending, filename)
+		w.lock.Unlock()
+
+		if _, err := os.Stat(filename); errors.Is(err, fs.ErrNotExist) && retry < reloadRetries {
+			w.schedule(filename, retry+1)
+			return
+		}
+
+		w.reload.Lock()
+		defer w.reload.Unlock()
+		if !w.stopped() {
+			w.callback(filename)
+		}
+	})
+}
+
+// watchFile adds file to the watched ones, if FileTemplate watches them.
+func (dom *DOM) watchFile(file string) {
+	if dom.watcher == nil || file == "" {
+		return
+	}
+	if diskPath, onDisk := dom.diskPath(file); onDisk {
+		if err := dom.watcher.add(diskPath); err != nil {
+			log.Println("Watcher error:", err)
+		}
+	}
+}
+
+// fileChanged renders again the template or the includes using filename, or
+// the whole template if it's an icon file. If the file can't be loaded, the
+// error is shown over the current UI instead.
+func (dom *DOM) fileChanged(filename string) {
+	if Icons.forget(filename) {
+		log.Println("File " + filename + " changed")
+		dom.reload(dom.source)
+		return
+	}
+
+	if dom.file != "" && dom.fileKey(dom.file) == filename {
+		log.Println("File " + dom.file + " changed")
+
+		content, err := dom.readFile(dom.file)
+		if err == nil {
+			_, err = Parser.parseRoot(string(content), dom.parseMode)
+		}
+		if err != nil {
+			dom.setReloadError(dom.file, err)
+			return
+		}
+
+		dom.reload(string(content))
+		return
+	}
+
+	// includes rendered by the items of a <list> or <tree> can't be rendered
+	// again on their own, so the whole template is
+	if file, inFragment := dom.includeCache.forget(filename); inFragment {
+		log.Println("File " + file + " changed")
+
+		content, err := dom.readFile(file)
+		if err == nil {
+			_, err = Parser.parseRoot(string(content), dom.parseMode)
+		}
+		if err != nil {
+			dom.setReloadError(file, err)
+			return
+		}
+
+		dom.reload(dom.source)
+		return
+	}
+
+	for _, view := range append([]*includeView{}, dom.includes...) {
+		// an include inside another one using the file is already rendered again
+		if view.removed || dom.fileKey(view.file) != filename {
+			continue
+		}
+		log.Println("File " + view.file + " changed")
+
+		content, err := view.read()
+		if err == nil {
+			_, err = Parser.parseRoot(content, dom.parseMode)
+		}
+		if err != nil {
+			dom.setReloadError(view.file, err)
+			continue
+		}
+
+		dom.setReloadError(view.file, nil)
+		view.render()
+	}
+}
+
+// reload renders the template again, keeping the reload errors of the files
+// it still uses that are still broken.
+func (dom *DOM) reload(content string) {
+	previous := dom.reloadErrors
+	dom.template(content, dom.file)
+
+	for file := range previous {
+		if !dom.includeCache.has(dom.fileKey(file)) {
+			continue
+		}
+		content, err := dom.readFile(file)
+		if err == nil {
+			_, err = Parser.parseRoot(string(content), dom.parseMode)
+		}
+		if err != nil {
+			dom.setReloadError(file, err)
+		}
+	}
+}
+
+// setReloadError shows, or with a nil err hides, the error loading file
+// over the UI.
+func (dom *DOM) setReloadError(file string, err error) {
+	if err != nil {
+		log.Println("Reload error:", err)
+		if dom.reloadErrors == nil {
+			dom.reloadErrors = make(map[string]error)
+		}
+		dom.reloadErrors[file] = err
+	} else if _, ok := dom.reloadErrors[file]; ok {
+		delete(dom.reloadErrors, file)
+	} else {
+		return
+	}
+
+	content := dom.root.Objects[:1]
+	if len(dom.reloadErrors) > 0 {
+		content = append(content, dom.reloadOverlay())
+	}
+	dom.root.Objects = content
+	dom.root.Refresh()
+}
+
+// reloadOverlay is a banner listing the reload errors, at the top of the UI.
+func (dom *DOM) reloadOverlay() fyne.CanvasObject {
+	var lines []string
+	for file, err := range dom.reloadErrors {
+		lines = append(lines, file+": "+err.Error())
+	}
+	sort.Strings(lines)
+
+	message := widget.NewLabelWithStyle(strings.Join(lines, "\n"), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	message.Wrapping = fyne.TextWrapWord
+
+	dismiss := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+		dom.reloadErrors = nil
+		dom.root.Objects = dom.root.Objects[:1]
+		dom.root.Refresh()
+	})
+	dismiss.Importance = widget.LowImportance
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameError))
+	banner := container.NewStack(background, container.NewBorder(nil, nil, nil, dismiss, message))
+
+	return container.NewBorder(banner, nil, nil, nil)
+}
@@ -0,0 +1,99 @@
+package reago_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+// reloadErrors returns the text of the reload error banner of dom.
+func reloadErrors(dom *reago.DOM) string {
+	objects := dom.GetRoot().(*fyne.Container).Objects
+	if len(objects) < 2 {
+		return ""
+	}
+	var texts []string
+	for _, obj := range test.LaidOutObjects(objects[1]) {
+		if label, ok := obj.(*widget.Label); ok {
+			texts = append(texts, label.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// skipWithoutWatcher skips tests watching files where fsnotify doesn't run,
+// e.g. js/wasm.
+func skipWithoutWatcher(t *testing.T) {
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		t.Skip("fsnotify doesn't run on " + runtime.GOOS)
+	}
+}
+
+// waitReload advances the clock until check passes, as file changes are seen
+// asynchronously and then debounced on the clock.
+func waitReload(h *reagotest.Harness, check func() bool) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for !check() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+		h.Advance(time.Second)
+	}
+	return true
+}
+
+func TestWatchKeepsErrorsOfBrokenIncludes(t *testing.T) {
+	skipWithoutWatcher(t)
+	h := reagotest.New(t)
+	// the test theme has no bold monospace font, used to show the broken
+	// include in the template
+	fyne.CurrentApp().Settings().SetTheme(theme.DefaultTheme())
+
+	dir := t.TempDir()
+	write := func(name string, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.xml", `<col><label id="title">Title</label><include src="part.xml" /></col>`)
+	write("part.xml", `<label>Part</label>`)
+
+	dom := reago.NewDOM()
+	watcher, err := dom.FileTemplate(filepath.Join(dir, "main.xml"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+	h.Mount(dom)
+
+	write("part.xml", `<label>Part</col>`)
+	if !waitReload(h, func() bool { return strings.Contains(reloadErrors(dom), "part.xml") }) {
+		t.Fatal("the broken include isn't reported")
+	}
+
+	write("main.xml", `<col><label id="title">New title</label><include src="part.xml" /></col>`)
+	if !waitReload(h, func() bool { return h.Text("#title") == "New title" }) {
+		t.Fatal("the template wasn't rendered again")
+	}
+	if !strings.Contains(reloadErrors(dom), "part.xml") {
+		t.Error("the error of the include still broken was dropped")
+	}
+
+	write("part.xml", `<label>Part</label>`)
+	if !waitReload(h, func() bool { return reloadErrors(dom) == "" }) {
+		t.Errorf("the error of the fixed include is still shown: %q", reloadErrors(dom))
+	}
+}
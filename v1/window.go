@@ -1,40 +1,133 @@
 package reago
 
 import (
+	"log"
+	"runtime"
+	"sync"
+
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
 )
 
-var mainApp fyne.App = nil
-var mainWindow *Window = nil
+type Window struct {
+	w           fyne.Window
+	id          string
+	app         *App
+	parent      *Window
+	children    []*Window
+	modal       bool
+	blocker     *modalBlocker
+	onClosed    func()
+	beforeClose func()
+	hideOnClose func()
+	remember    bool
+	hidden      bool
+	toasts      *toastHost
+	dom         *DOM
+	menuRefs    map[string]*fyne.MenuItem
+	menuActions map[string][]*fyne.MenuItem
+	shortcuts   map[string][]*shortcutBinding
+}
 
-func init() {
-	mainApp = app.New()
+// NewWindow creates a window in the DefaultApp, identified by its title. The
+// first window shown runs the app, so that Show blocks until it quits.
+func NewWindow(title string, width float32, height float32) *Window {
+	return DefaultApp().newWindow(title, title, width, height)
 }
 
-type Window struct {
-	w        fyne.Window
-	menuRefs map[string]*fyne.MenuItem
+// NewChild creates a window that is closed together with this one.
+func (window *Window) NewChild(id string, title string, width float32, height float32) *Window {
+	child := window.app.newWindow(id, title, width, height)
+	child.parent = window
+	window.children = append(window.children, child)
+	return child
 }
 
-func NewWindow(title string, width float32, height float32) *Window {
-	window := &Window{}
-	window.w = mainApp.NewWindow(title)
-	window.w.Resize(fyne.NewSize(width, height))
-	return window
+// NewModal creates a child window that blocks input to this one while it is
+// shown.
+func (window *Window) NewModal(id string, title string, width float32, height float32) *Window {
+	child := window.NewChild(id, title, width, height)
+	child.modal = true
+	return child
+}
+
+func (window *Window) GetID() string {
+	return window.id
+}
+
+func (window *Window) GetApp() *App {
+	return window.app
+}
+
+func (window *Window) GetParent() *Window {
+	return window.parent
+}
+
+func (window *Window) Canvas() fyne.Canvas {
+	return window.w.Canvas()
+}
+
+// canvasWindows maps the canvas of every open window to it.
+var canvasWindows sync.Map // map[fyne.Canvas]*Window
+
+// windowOf returns the window showing obj, or nil.
+func windowOf(obj fyne.CanvasObject) *Window {
+	c := fyne.CurrentApp().Driver().CanvasForObject(obj)
+	if c == nil {
+		return nil
+	}
+	if window, ok := canvasWindows.Load(c); ok {
+		return window.(*Window)
+	}
+	return nil
 }
 
 func (window *Window) Show(d *DOM) {
-	window.w.SetContent(d.GetRoot())
+	if window.dom != nil && window.dom != d {
+		window.dom.window = nil
+	}
+	window.dom = d
+	d.window = window
+	window.syncShortcuts()
 
-	if mainWindow == nil {
-		mainWindow = window
-		window.w.ShowAndRun()
-	} else {
-		window.w.Show()
+	window.toasts.dom = d
+	window.w.SetContent(container.NewStack(d.GetRoot(), window.toasts.obj))
+	window.restoreLayout()
+
+	if window.modal && window.parent != nil && window.blocker == nil {
+		window.blocker = newModalBlocker(window)
+		window.parent.w.Canvas().Overlays().Add(window.blocker)
+	}
+
+	window.w.Show()
+	window.hidden = false
+
+	window.app.lock.Lock()
+	run := window.app.autoRun && !window.app.running
+	window.app.lock.Unlock()
+
+	if run {
+		window.app.Run()
 	}
 }
 
+// Hide hides the window without closing it.
+func (window *Window) Hide() {
+	window.w.Hide()
+	window.hidden = true
+}
+
+// Reveal shows the window again after Hide.
+func (window *Window) Reveal() {
+	window.w.Show()
+	window.hidden = false
+}
+
+// IsHidden reports whether the window is hidden, or was never shown.
+func (window *Window) IsHidden() bool {
+	return window.hidden
+}
+
 func (window *Window) OnFileDropped(callback func([]string)) {
 	window.w.SetOnDropped(func(pos fyne.Position, uris []fyne.URI) {
 		var paths []string
@@ -90,14 +183,60 @@ func (window *Window) SetPadded(value bool) {
 }
 
 func (window *Window) OnClosed(callback func()) {
-	window.w.SetOnClosed(callback)
+	window.onClosed = callback
+}
+
+func (window *Window) closed() {
+	canvasWindows.Delete(window.w.Canvas())
+
+	if window.remember {
+		if err := window.SaveState(); err != nil {
+			log.Println("can't save window state:", err)
+		}
+	}
+
+	for _, child := range append([]*Window{}, window.children...) {
+		child.Close()
+	}
+
+	if window.blocker != nil {
+		window.parent.w.Canvas().Overlays().Remove(window.blocker)
+		window.blocker = nil
+	}
+
+	if window.parent != nil {
+		siblings := window.parent.children
+		for i, sibling := range siblings {
+			if sibling == window {
+				window.parent.children = append(siblings[:i:i], siblings[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if window.onClosed != nil {
+		window.onClosed()
+	}
+
+	window.app.removeWindow(window)
 }
 
 func (window *Window) OnBeforeClose(callback func()) {
-	window.w.SetCloseIntercept(func() {
-		callback()
-		window.Close()
-	})
+	window.beforeClose = callback
+	window.w.SetCloseIntercept(window.interceptClose)
+}
+
+// interceptClose runs the OnBeforeClose callback, then closes the window, or
+// hides it when the tray toggles it.
+func (window *Window) interceptClose() {
+	if window.beforeClose != nil {
+		window.beforeClose()
+	}
+	if window.hideOnClose != nil {
+		window.hideOnClose()
+		return
+	}
+	window.Close()
 }
 
 func (window *Window) Close() {
@@ -114,20 +253,97 @@ func (window *Window) SetClipboard(value string) {
 
 func (window *Window) SetMainMenu(menus ...*fyne.Menu) {
 	window.menuRefs = make(map[string]*fyne.MenuItem)
+	window.removeShortcuts(func(binding *shortcutBinding) bool {
+		return binding.kind == shortcutMenu
+	})
 
 	for _, menu := range menus {
 		if menu != nil {
-			for _, item := range menu.Items {
-				if item != nil {
-					window.menuRefs[menu.Label+"/"+item.Label] = item
+			window.registerMenuItems(menu.Label, menu)
+		}
+	}
+
+	window.w.SetMainMenu(fyne.NewMainMenu(menus...))
+}
+
+// SetMainMenuTemplate sets the main menu from a <menubar> template bound to
+// the given DOM. Items with an id can be retrieved with GetMenuItem.
+func (window *Window) SetMainMenuTemplate(dom *DOM, content string) error {
+	menus, err := dom.MenuTemplate(content)
+	if err != nil {
+		return err
+	}
+
+	window.menuActions = make(map[string][]*fyne.MenuItem)
+	for callback, items := range dom.menuActions {
+		window.menuActions[callback] = items
+
+		// display shortcuts bound with Window.Shortcut
+		for _, item := range items {
+			for _, bindings := range window.shortcuts {
+				for _, binding := range bindings {
+					if binding.kind == shortcutWindow && binding.callback == callback && item.Shortcut == nil {
+						item.Shortcut = binding.shortcut
+					}
 				}
 			}
 		}
 	}
 
-	window.w.SetMainMenu(fyne.NewMainMenu(menus...))
+	window.SetMainMenu(menus...)
+	for id, item := range dom.menuRefs {
+		window.menuRefs[id] = item
+	}
+
+	return nil
+}
+
+// registerMenuItems registers every item by its label path, e.g.
+// "File/Recent/Item", and makes their shortcuts trigger them.
+func (window *Window) registerMenuItems(path string, menu *fyne.Menu) {
+	if menu == nil {
+		return
+	}
+
+	for _, item := range menu.Items {
+		if item == nil || item.IsSeparator {
+			continue
+		}
+
+		window.menuRefs[path+"/"+item.Label] = item
+		window.registerMenuItems(path+"/"+item.Label, item.ChildMenu)
+
+		// macOS native menus handle their own key equivalents
+		if shortcut, ok := item.Shortcut.(fyne.KeyboardShortcut); ok && runtime.GOOS != "darwin" {
+			window.registerMenuShortcut(item, shortcut)
+		}
+	}
+}
+
+func (window *Window) registerMenuShortcut(item *fyne.MenuItem, shortcut fyne.KeyboardShortcut) {
+	binding := &shortcutBinding{kind: shortcutMenu, shortcut: shortcut, action: func() {
+		if !item.Disabled && item.Action != nil {
+			item.Action()
+		}
+	}}
+
+	err := window.addShortcut(binding)
+	if err == nil {
+		return
+	}
+
+	// the shortcut is only displayed if it was bound to the item callback
+	for _, existing := range window.shortcuts[binding.name()] {
+		for _, other := range window.menuActions[existing.callback] {
+			if other == item {
+				return
+			}
+		}
+	}
+	log.Println("menu shortcut error:", err)
 }
 
+// GetMenuItem returns a main menu item by its template id or label path.
 func (window *Window) GetMenuItem(id string) *fyne.MenuItem {
 	return window.menuRefs[id]
 }
@@ -1,129 +1,414 @@
 package reago
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"image/color"
+	"log"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
-func (window *Window) DlgColorPicker(title string, message string, callback func(color.Color)) {
-	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
+var errFragment = errors.New("can't show dialog on a fragment")
+
+// DialogResult is the outcome of a dialog. Cancelled is set when the user
+// dismissed it, Err when it failed.
+type DialogResult[T any] struct {
+	Value     T
+	Cancelled bool
+	Err       error
+}
+
+// Dialog is a shown dialog. Its result is delivered once to the callback
+// given when showing it, and can be read any number of times with Result and
+// Wait.
+type Dialog[T any] struct {
+	hide     func()
+	callback func(DialogResult[T])
+	once     sync.Once
+	done     chan struct{}
+	value    DialogResult[T]
+}
+
+func newDialog[T any](callback func(DialogResult[T])) *Dialog[T] {
+	return &Dialog[T]{callback: callback, done: make(chan struct{})}
+}
+
+// resolve sets the result of the dialog, unless it has one already. The
+// callback runs outside of the once, so that it can close the dialog.
+func (d *Dialog[T]) resolve(result DialogResult[T]) {
+	resolved := false
+	d.once.Do(func() {
+		d.value = result
+		resolved = true
+	})
+	if !resolved {
 		return
 	}
 
-	dialog.NewColorPicker("Pick a Color", "Choose a color from the palette", func(c color.Color) {
-		callback(c)
-	}, window.w)
+	if d.callback != nil {
+		d.callback(result)
+	}
+	close(d.done)
 }
 
-func (window *Window) DlgFileOpen(callback func(string)) {
+func (d *Dialog[T]) fail(err error) *Dialog[T] {
+	d.resolve(DialogResult[T]{Err: err})
+	return d
+}
+
+// Result returns a channel that receives the result once the dialog closes.
+// Each call returns a new channel.
+func (d *Dialog[T]) Result() <-chan DialogResult[T] {
+	result := make(chan DialogResult[T], 1)
+	select {
+	case <-d.done:
+		result <- d.value
+	default:
+		go func() {
+			<-d.done
+			result <- d.value
+		}()
+	}
+	return result
+}
+
+// Wait blocks until the dialog closes, or until ctx is done, which closes the
+// dialog as cancelled with the context error. Don't call it from a callback
+// run by the UI, as the dialog could never close.
+func (d *Dialog[T]) Wait(ctx context.Context) DialogResult[T] {
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+		d.cancel(ctx.Err())
+		<-d.done
+	}
+	return d.value
+}
+
+// Close dismisses the dialog, as if the user cancelled it.
+func (d *Dialog[T]) Close() {
+	d.cancel(nil)
+}
+
+func (d *Dialog[T]) cancel(err error) {
+	d.resolve(DialogResult[T]{Cancelled: true, Err: err})
+	if d.hide != nil {
+		d.hide()
+	}
+}
+
+// FileOptions configures the file and folder dialogs.
+type FileOptions struct {
+	// Extensions limits the files shown, e.g. []string{".png", ".jpg"}.
+	Extensions []string
+	// MimeTypes limits the files shown, e.g. []string{"image/*"}.
+	MimeTypes []string
+	// Location is the directory the dialog starts in.
+	Location string
+	// FileName is the name suggested by the save dialog.
+	FileName string
+	// ConfirmText and DismissText replace the labels of the buttons.
+	ConfirmText string
+	DismissText string
+}
+
+func (opts FileOptions) apply(fd *dialog.FileDialog) {
+	if len(opts.Extensions) > 0 {
+		fd.SetFilter(storage.NewExtensionFileFilter(opts.Extensions))
+	} else if len(opts.MimeTypes) > 0 {
+		fd.SetFilter(storage.NewMimeTypeFileFilter(opts.MimeTypes))
+	}
+
+	// a location that no longer exists falls back to the default one
+	if opts.Location != "" {
+		if lister, err := storage.ListerForURI(storage.NewFileURI(opts.Location)); err == nil {
+			fd.SetLocation(lister)
+		}
+	}
+
+	if opts.FileName != "" {
+		fd.SetFileName(opts.FileName)
+	}
+	if opts.ConfirmText != "" {
+		fd.SetConfirmText(opts.ConfirmText)
+	}
+	if opts.DismissText != "" {
+		fd.SetDismissText(opts.DismissText)
+	}
+}
+
+// OpenFile asks for a file to open. The file isn't kept open: read it from
+// the returned URI, e.g. with storage.Reader or os.ReadFile(uri.Path()).
+func (window *Window) OpenFile(opts FileOptions, callback func(DialogResult[fyne.URI])) *Dialog[fyne.URI] {
+	d := newDialog(callback)
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
-		return
+		return d.fail(errFragment)
 	}
 
-	dialog.ShowFileOpen(func(f fyne.URIReadCloser, err error) {
-		if err != nil {
-			fmt.Println(err)
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			d.resolve(DialogResult[fyne.URI]{Cancelled: reader == nil && err == nil, Err: err})
 			return
 		}
 
-		callback(f.URI().String())
+		uri := reader.URI()
+		err = reader.Close()
+		d.resolve(DialogResult[fyne.URI]{Value: uri, Err: err})
 	}, window.w)
+	opts.apply(fd)
+
+	d.hide = fd.Hide
+	fd.Show()
+	return d
 }
 
-func (window *Window) DlgFileSave(callback func(string)) {
+// SaveFile asks where to save a file. The file is created empty.
+func (window *Window) SaveFile(opts FileOptions, callback func(DialogResult[fyne.URI])) *Dialog[fyne.URI] {
+	d := newDialog(callback)
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
-		return
+		return d.fail(errFragment)
 	}
 
-	dialog.ShowFileSave(func(f fyne.URIWriteCloser, err error) {
-		if err != nil {
-			fmt.Println(err)
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			d.resolve(DialogResult[fyne.URI]{Cancelled: writer == nil && err == nil, Err: err})
 			return
 		}
-		callback(f.URI().String())
+
+		uri := writer.URI()
+		err = writer.Close()
+		d.resolve(DialogResult[fyne.URI]{Value: uri, Err: err})
 	}, window.w)
+	opts.apply(fd)
+
+	d.hide = fd.Hide
+	fd.Show()
+	return d
 }
 
-func (window *Window) DlgFolderOpen(callback func(string)) {
+// OpenFolder asks for a folder.
+func (window *Window) OpenFolder(opts FileOptions, callback func(DialogResult[fyne.URI])) *Dialog[fyne.URI] {
+	d := newDialog(callback)
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
-		return
+		return d.fail(errFragment)
 	}
 
-	dialog.ShowFolderOpen(func(f fyne.ListableURI, err error) {
-		if err != nil {
+	fd := dialog.NewFolderOpen(func(folder fyne.ListableURI, err error) {
+		if err != nil || folder == nil {
+			d.resolve(DialogResult[fyne.URI]{Cancelled: folder == nil && err == nil, Err: err})
 			return
 		}
-		callback(f.String())
+		d.resolve(DialogResult[fyne.URI]{Value: folder})
 	}, window.w)
+	opts.apply(fd)
+
+	d.hide = fd.Hide
+	fd.Show()
+	return d
 }
 
-func (window *Window) DlgAlert(title string, message string) {
+// Confirm asks a yes/no question. Value is true on yes; no counts as
+// cancelled.
+func (window *Window) Confirm(title string, message string, callback func(DialogResult[bool])) *Dialog[bool] {
+	d := newDialog(callback)
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
-		return
+		return d.fail(errFragment)
 	}
 
-	minSize := fyne.NewSize(200, 100)
-	minRect := canvas.NewRectangle(color.Transparent)
-	minRect.SetMinSize(minSize)
+	dlg := dialog.NewConfirm(title, message, func(ok bool) {
+		d.resolve(DialogResult[bool]{Value: ok, Cancelled: !ok})
+	}, window.w)
 
-	messageLabel := widget.NewLabel(message)
-	content := container.NewStack(minRect, container.NewCenter(messageLabel))
+	d.hide = dlg.Hide
+	dlg.Show()
+	return d
+}
+
+// PickColor asks for a color.
+func (window *Window) PickColor(title string, message string, callback func(DialogResult[color.Color])) *Dialog[color.Color] {
+	d := newDialog(callback)
+	if window.w == nil {
+		return d.fail(errFragment)
+	}
+
+	picker := newColorPicker(theme.Color(theme.ColorNamePrimary))
+	dlg := dialog.NewCustomConfirm(title, "Confirm", "Cancel", picker.content(message), func(confirmed bool) {
+		if confirmed {
+			d.resolve(DialogResult[color.Color]{Value: picker.color})
+		} else {
+			d.resolve(DialogResult[color.Color]{Cancelled: true})
+		}
+	}, window.w)
+
+	d.hide = dlg.Hide
+	dlg.Show()
+	return d
+}
+
+// Alert shows a message. It resolves once dismissed.
+func (window *Window) Alert(title string, message string) *Dialog[struct{}] {
+	d := newDialog[struct{}](nil)
+	if window.w == nil {
+		return d.fail(errFragment)
+	}
+
+	minRect := canvas.NewRectangle(color.Transparent)
+	minRect.SetMinSize(fyne.NewSize(200, 100))
+	content := container.NewStack(minRect, container.NewCenter(widget.NewLabel(message)))
 
 	dlg := dialog.NewCustom(title, "OK", content, window.w)
+	dlg.SetOnClosed(func() {
+		d.resolve(DialogResult[struct{}]{})
+	})
+
+	d.hide = dlg.Hide
 	dlg.Show()
+	return d
 }
 
-func (window *Window) DlgError(title string, message string) {
+// ShowError shows an error. It resolves once dismissed.
+func (window *Window) ShowError(err error) *Dialog[struct{}] {
+	d := newDialog[struct{}](nil)
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
-		return
+		return d.fail(errFragment)
+	}
+
+	dlg := dialog.NewError(err, window.w)
+	dlg.SetOnClosed(func() {
+		d.resolve(DialogResult[struct{}]{})
+	})
+
+	d.hide = dlg.Hide
+	dlg.Show()
+	return d
+}
+
+func logDialogError(err error) {
+	if err != nil {
+		log.Println("dialog error:", err)
 	}
+}
+
+func (window *Window) DlgColorPicker(title string, message string, callback func(color.Color)) {
+	window.PickColor(title, message, func(result DialogResult[color.Color]) {
+		logDialogError(result.Err)
+		if result.Err == nil && !result.Cancelled {
+			callback(result.Value)
+		}
+	})
+}
 
-	dialog.ShowError(errors.New(message), window.w)
+func (window *Window) DlgFileOpen(callback func(string)) {
+	window.OpenFile(FileOptions{}, func(result DialogResult[fyne.URI]) {
+		logDialogError(result.Err)
+		if result.Err == nil && !result.Cancelled {
+			callback(result.Value.String())
+		}
+	})
 }
 
-func (window *Window) DlgConfirm(title string, message string, callback func(bool)) {
+func (window *Window) DlgFileSave(callback func(string)) {
+	window.SaveFile(FileOptions{}, func(result DialogResult[fyne.URI]) {
+		logDialogError(result.Err)
+		if result.Err == nil && !result.Cancelled {
+			callback(result.Value.String())
+		}
+	})
+}
+
+func (window *Window) DlgFolderOpen(callback func(string)) {
+	window.OpenFolder(FileOptions{}, func(result DialogResult[fyne.URI]) {
+		logDialogError(result.Err)
+		if result.Err == nil && !result.Cancelled {
+			callback(result.Value.String())
+		}
+	})
+}
+
+func (window *Window) DlgAlert(title string, message string) {
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
+		logDialogError(errFragment)
 		return
 	}
+	window.Alert(title, message)
+}
 
-	dialog.ShowConfirm(title, message, func(b bool) {
-		callback(b)
-	}, window.w)
+func (window *Window) DlgError(title string, message string) {
+	if window.w == nil {
+		logDialogError(errFragment)
+		return
+	}
+	content := widget.NewLabel(message)
+	content.Wrapping = fyne.TextWrapWord
+	dialog.NewCustom(title, "OK", container.NewStack(content), window.w).Show()
 }
 
+func (window *Window) DlgConfirm(title string, message string, callback func(bool)) {
+	window.Confirm(title, message, func(result DialogResult[bool]) {
+		logDialogError(result.Err)
+		callback(result.Value)
+	})
+}
+
+// progressBars are the bars of the dialogs shown by DlgProgress with a total.
+var progressBars sync.Map
+
+// DlgProgress shows a progress bar, calling callback once the returned dialog
+// is hidden. With a total above 0, the bar goes from 0 to total as reported
+// with DlgProgressSet; it is infinite otherwise. Use Progress to run work
+// while reporting its progress.
 func (window *Window) DlgProgress(title string, message string, total int, callback func()) *dialog.CustomDialog {
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
+		logDialogError(errFragment)
 		return nil
 	}
 
-	dlg := dialog.NewCustomWithoutButtons("Loading", widget.NewProgressBarInfinite(), window.w)
+	var bar fyne.CanvasObject = widget.NewProgressBarInfinite()
+	if total > 0 {
+		determinate := widget.NewProgressBar()
+		determinate.Max = float64(total)
+		bar = determinate
+	}
+
+	content := container.NewVBox(widget.NewLabel(message), bar)
+	dlg := dialog.NewCustomWithoutButtons(title, content, window.w)
+	if determinate, ok := bar.(*widget.ProgressBar); ok {
+		progressBars.Store(dlg, determinate)
+	}
+	dlg.SetOnClosed(func() {
+		progressBars.Delete(dlg)
+		if infinite, ok := bar.(*widget.ProgressBarInfinite); ok {
+			infinite.Stop()
+		}
+		if callback != nil {
+			callback()
+		}
+	})
 	dlg.Show()
 
 	return dlg
 }
 
+// DlgProgressSet sets how much of the total of a dialog shown by DlgProgress
+// is done.
+func (window *Window) DlgProgressSet(dlg *dialog.CustomDialog, done int) {
+	if bar, ok := progressBars.Load(dlg); ok {
+		bar.(*widget.ProgressBar).SetValue(float64(done))
+	}
+}
+
 func (window *Window) DlgModal(title string, content string) {
 	if window.w == nil {
-		fmt.Println("Error: can't show dialog on a fragment")
+		logDialogError(errFragment)
 		return
 	}
-
-	fragment := NewDOM()
-	fragment.Template(content)
-	dialog.ShowCustom(title, "Close", fragment.root, window.w)
+	window.Modal(content, ModalOptions{Title: title}, nil)
 }
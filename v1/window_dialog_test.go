@@ -0,0 +1,138 @@
+package reago_test
+
+import (
+	"context"
+	"image/color"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	reago "github.com/victormga/reago/v1"
+	"github.com/victormga/reago/v1/reagotest"
+)
+
+// dialogButton returns the button labelled text of the dialog on top of the
+// window.
+func dialogButton(t *testing.T, h *reagotest.Harness, text string) *widget.Button {
+	t.Helper()
+
+	top := h.Window.Canvas().Overlays().Top()
+	if top == nil {
+		t.Fatal("no dialog is shown")
+	}
+	for _, obj := range test.LaidOutObjects(top) {
+		if button, ok := obj.(*widget.Button); ok && button.Text == text {
+			return button
+		}
+	}
+	t.Fatalf("the dialog has no %s button", text)
+	return nil
+}
+
+func tapDialogButton(t *testing.T, h *reagotest.Harness, text string) {
+	t.Helper()
+	test.Tap(dialogButton(t, h, text))
+}
+
+// setDialogEntry sets the text of the first entry of the dialog on top of
+// the window.
+func setDialogEntry(t *testing.T, h *reagotest.Harness, text string) {
+	t.Helper()
+
+	for _, obj := range test.LaidOutObjects(h.Window.Canvas().Overlays().Top()) {
+		if entry, ok := obj.(*widget.Entry); ok {
+			entry.SetText(text)
+			return
+		}
+	}
+	t.Fatal("the dialog has no entry")
+}
+
+func mountEmpty(h *reagotest.Harness) {
+	dom := reago.NewDOM()
+	dom.Template(`<label>Window</label>`)
+	h.Mount(dom)
+}
+
+func TestPickColorConfirm(t *testing.T) {
+	h := reagotest.New(t)
+	mountEmpty(h)
+
+	var results []reago.DialogResult[color.Color]
+	h.Window.PickColor("Color", "Pick one", func(result reago.DialogResult[color.Color]) {
+		results = append(results, result)
+	})
+	setDialogEntry(t, h, "#ff0000")
+	tapDialogButton(t, h, "Confirm")
+
+	if len(results) != 1 || results[0].Cancelled {
+		t.Fatalf("results %+v, expected a picked color", results)
+	}
+	if r, g, b, _ := results[0].Value.RGBA(); r>>8 != 0xff || g != 0 || b != 0 {
+		t.Errorf("picked %v, expected red", results[0].Value)
+	}
+}
+
+func TestPickColorCancel(t *testing.T) {
+	h := reagotest.New(t)
+	mountEmpty(h)
+
+	var results []reago.DialogResult[color.Color]
+	h.Window.PickColor("Color", "", func(result reago.DialogResult[color.Color]) {
+		results = append(results, result)
+	})
+	tapDialogButton(t, h, "Cancel")
+
+	if len(results) != 1 || !results[0].Cancelled {
+		t.Fatalf("results %+v, expected cancelled", results)
+	}
+}
+
+func TestDialogCallbackClosingTheDialog(t *testing.T) {
+	h := reagotest.New(t)
+	mountEmpty(h)
+
+	var d *reago.Dialog[color.Color]
+	calls := 0
+	d = h.Window.PickColor("Color", "", func(result reago.DialogResult[color.Color]) {
+		calls++
+		d.Close()
+	})
+
+	confirm := dialogButton(t, h, "Confirm")
+	done := make(chan struct{})
+	go func() {
+		test.Tap(confirm)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("closing the dialog from its callback deadlocked")
+	}
+	if calls != 1 {
+		t.Errorf("callback called %d times, expected once", calls)
+	}
+}
+
+func TestDialogResultReadSeveralTimes(t *testing.T) {
+	h := reagotest.New(t)
+	mountEmpty(h)
+
+	d := h.Window.PickColor("Color", "", nil)
+	pending := d.Result()
+	tapDialogButton(t, h, "Cancel")
+
+	for _, result := range []reago.DialogResult[color.Color]{
+		<-pending,
+		<-d.Result(),
+		<-d.Result(),
+		d.Wait(context.Background()),
+	} {
+		if !result.Cancelled {
+			t.Errorf("result %+v, expected cancelled", result)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package reago
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+)
+
+// windowState is what a remembered window restores on the next launch.
+// fyne doesn't expose the position of windows, so it isn't part of it.
+type windowState struct {
+	Width      float32            `json:"width,omitempty"`
+	Height     float32            `json:"height,omitempty"`
+	FullScreen bool               `json:"fullscreen,omitempty"`
+	Splits     map[string]float64 `json:"splits,omitempty"`
+	Tabs       map[string]int     `json:"tabs,omitempty"`
+}
+
+// SetWindowStateFile sets where remembered windows are saved. It defaults to
+// reago/<executable>/windows.json in the user config directory.
+func (a *App) SetWindowStateFile(path string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.stateFile = path
+	a.windowStates = nil
+}
+
+func (a *App) windowStateFile() string {
+	if a.stateFile != "" {
+		return a.stateFile
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	name := strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	return filepath.Join(dir, "reago", name, "windows.json")
+}
+
+// windowState returns the saved state of the window with the given id,
+// loading the state file on first use. The lock must be held.
+func (a *App) windowState(id string) *windowState {
+	if a.windowStates == nil {
+		a.windowStates = make(map[string]*windowState)
+
+		bytes, err := os.ReadFile(a.windowStateFile())
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Println("can't read window state:", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(bytes, &a.windowStates); err != nil {
+				log.Println("can't read window state:", err)
+			}
+		}
+	}
+
+	state, ok := a.windowStates[id]
+	if !ok {
+		state = &windowState{}
+		a.windowStates[id] = state
+	}
+	if state.Splits == nil {
+		state.Splits = make(map[string]float64)
+	}
+	if state.Tabs == nil {
+		state.Tabs = make(map[string]int)
+	}
+	return state
+}
+
+func (a *App) saveWindowStates() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.windowStates == nil {
+		return nil
+	}
+
+	bytes, err := json.MarshalIndent(a.windowStates, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	path := a.windowStateFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0o644)
+}
+
+// Remember makes the window save its size, fullscreen flag, and the offsets
+// of <split> and selected tab of <tabs> tags that have an id, and restores
+// them now and on the next launch. The window id is the key they are saved
+// under, so it should be unique and stable.
+func (window *Window) Remember() {
+	window.remember = true
+
+	window.app.lock.Lock()
+	state := *window.app.windowState(window.id)
+	window.app.lock.Unlock()
+
+	if state.Width > 0 && state.Height > 0 {
+		window.Resize(state.Width, state.Height)
+	}
+	if state.FullScreen {
+		window.SetFullScreen(true)
+	}
+
+	window.restoreLayout()
+}
+
+// SaveState writes the state of the window now, instead of waiting for it to
+// close.
+func (window *Window) SaveState() error {
+	window.captureState()
+	return window.app.saveWindowStates()
+}
+
+func (window *Window) captureState() {
+	if !window.remember {
+		return
+	}
+
+	window.app.lock.Lock()
+	defer window.app.lock.Unlock()
+
+	state := window.app.windowState(window.id)
+	state.FullScreen = window.IsFullScreen()
+	if !state.FullScreen {
+		size := window.w.Canvas().Size()
+		if size.Width > 0 && size.Height > 0 {
+			state.Width, state.Height = size.Width, size.Height
+		}
+	}
+
+	if window.dom == nil {
+		return
+	}
+	for id, obj := range window.dom.refs {
+		switch obj := obj.(type) {
+		case *container.Split:
+			state.Splits[id] = obj.Offset
+		case *container.AppTabs:
+			state.Tabs[id] = obj.SelectedIndex()
+		}
+	}
+}
+
+// restoreLayout applies the saved split offsets and tabs to the shown DOM.
+func (window *Window) restoreLayout() {
+	if !window.remember || window.dom == nil {
+		return
+	}
+
+	// captureState writes the maps, so they're copied under the lock
+	window.app.lock.Lock()
+	state := window.app.windowState(window.id)
+	splits := maps.Clone(state.Splits)
+	selected := maps.Clone(state.Tabs)
+	window.app.lock.Unlock()
+
+	for id, offset := range splits {
+		if split, ok := window.dom.refs[id].(*container.Split); ok {
+			split.SetOffset(offset)
+		}
+	}
+	for id, index := range selected {
+		if tabs, ok := window.dom.refs[id].(*container.AppTabs); ok && index >= 0 && index < len(tabs.Items) {
+			tabs.SelectIndex(index)
+		}
+	}
+}
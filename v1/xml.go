@@ -2,6 +2,7 @@ package reago
 
 import (
 	"encoding/xml"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -11,6 +12,9 @@ type XMLNode struct {
 	Attrs   []xml.Attr `xml:",any,attr"`
 	Content string     `xml:",chardata"`
 	Nodes   []XMLNode  `xml:",any"`
+	// Children are the elements and text runs in template order, only kept
+	// by ParseLenient.
+	Children []XMLNode `xml:"-"`
 }
 
 func (node *XMLNode) GetTag() string {
@@ -94,9 +98,7 @@ func (node *XMLNode) BindCallback(name string, target *DOM) func() {
 	if node.HasBind(name) {
 		bind := node.GetBind(name)
 		return func() {
-			if callback, ok := target.callbacks[bind]; ok {
-				callback(node)
-			}
+			target.invoke(bind, node)
 		}
 	}
 	return nil
@@ -134,29 +136,39 @@ func (node *XMLNode) BindList(name string, target *DOM, update func([]any)) func
 }
 
 func (node *XMLNode) BindString(name string, target *DOM, update func(string)) func(string) {
-	value := node.GetAttr(name)
+	value := node.unbound().GetAttr(name)
 	bind := node.GetBind(name)
 	return bindToState(value, bind, target.state, target.state.GetString, update)
 }
 
 func (node *XMLNode) BindInt(name string, target *DOM, update func(int)) func(int) {
-	value := node.GetAttrInt(name)
+	value := node.unbound().GetAttrInt(name)
 	bind := node.GetBind(name)
 	return bindToState(value, bind, target.state, target.state.GetInt, update)
 }
 
 func (node *XMLNode) BindFloat(name string, target *DOM, update func(float64)) func(float64) {
-	value := node.GetAttrFloat(name)
+	value := node.unbound().GetAttrFloat(name)
 	bind := node.GetBind(name)
 	return bindToState(value, bind, target.state, target.state.GetFloat, update)
 }
 
 func (node *XMLNode) BindBool(name string, target *DOM, update func(bool)) func(bool) {
-	value := node.GetAttrBool(name)
+	value := node.unbound().GetAttrBool(name)
 	bind := node.GetBind(name)
 	return bindToState(value, bind, target.state, target.state.GetBool, update)
 }
 
+// unbound returns node without its bind: attributes, for the Bind helpers
+// to read the literal value from, so bind:value isn't taken for value.
+func (node *XMLNode) unbound() *XMLNode {
+	unbound := *node
+	unbound.Attrs = slices.DeleteFunc(slices.Clone(node.Attrs), func(attr xml.Attr) bool {
+		return attr.Name.Space == "bind"
+	})
+	return &unbound
+}
+
 func bindToState[T comparable](
 	value T,
 	bind string,
@@ -0,0 +1,26 @@
+package reago
+
+import "testing"
+
+func TestBindStringIgnoresBindAttribute(t *testing.T) {
+	root, err := Parser.parseRoot(`<input bind:value="user" />`, ParseStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GetAttr and HasAttr match the local name, whatever the namespace
+	if !root.HasAttr("value") || root.GetAttr("value") != "user" {
+		t.Errorf("GetAttr(value) = %q, expected the bind: attribute", root.GetAttr("value"))
+	}
+
+	dom := NewDOM()
+	dom.UseState().String("user", "ana")
+	var updates []string
+	root.BindString("value", dom, func(value string) {
+		updates = append(updates, value)
+	})
+	// the state is delivered later, the literal value right away
+	if len(updates) != 0 {
+		t.Errorf("bound value updated with %q, expected no literal value", updates)
+	}
+}